@@ -21,39 +21,508 @@ import (
 )
 
 // HTTPHeader describes a custom header to be sent in HTTP(S) probes.
+// +kubebuilder:validation:XValidation:rule="!has(self.value) || !has(self.valueFrom)",message="only one of value or valueFrom may be set"
 type HTTPHeader struct {
 	// name is the header field name.
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 	// value is the header field value.
-	Value string `json:"value"`
+	// Mutually exclusive with valueFrom.
+	// +optional
+	Value string `json:"value,omitempty"`
+	// valueFrom reads the header field value from a Secret or ConfigMap key when the
+	// probe runs, so that tokens do not have to be stored in the BootDependency.
+	// Mutually exclusive with value.
+	// +optional
+	ValueFrom *HTTPHeaderSource `json:"valueFrom,omitempty"`
+}
+
+// HTTPHeaderSource selects the key of a Secret or ConfigMap in the BootDependency's
+// namespace that holds an HTTP header value. Exactly one of secretKeyRef or
+// configMapKeyRef must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.secretKeyRef) != has(self.configMapKeyRef)",message="exactly one of secretKeyRef or configMapKeyRef must be set"
+type HTTPHeaderSource struct {
+	// secretKeyRef selects a key of a Secret.
+	// +optional
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// configMapKeyRef selects a key of a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// GRPCProbe configures a probe that uses the gRPC Health Checking Protocol
+// (grpc.health.v1.Health/Check). The dependency is only considered ready when
+// the server reports SERVING for the requested service.
+// +kubebuilder:validation:XValidation:rule="!has(self.insecure) || !self.insecure || (has(self.tls) && self.tls)",message="insecure requires tls to be enabled"
+type GRPCProbe struct {
+	// service is the service name sent in the HealthCheckRequest.
+	// When omitted, the overall health of the server is queried.
+	// +optional
+	Service string `json:"service,omitempty"`
+
+	// tls enables TLS on the gRPC connection. Defaults to false (plaintext).
+	// +optional
+	TLS bool `json:"tls,omitempty"`
+
+	// insecure controls whether TLS certificate verification is skipped.
+	// Only meaningful when tls is true. Defaults to false.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// SecretCredentialsRef references a Secret in the BootDependency's namespace that
+// holds the username and password a probe authenticates with.
+type SecretCredentialsRef struct {
+	// name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// usernameKey is the key in the Secret that holds the username.
+	// Defaults to "username".
+	// +kubebuilder:default="username"
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// passwordKey is the key in the Secret that holds the password.
+	// Defaults to "password".
+	// +kubebuilder:default="password"
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// PostgresProbe configures a probe that speaks the PostgreSQL wire protocol.
+// Without credentials the probe behaves like pg_isready: the server is ready once it
+// answers the startup message with an authentication request instead of an error such
+// as "the database system is in recovery mode". With credentials it additionally
+// authenticates and runs query, and also detects "too many clients already".
+// +kubebuilder:validation:XValidation:rule="!has(self.query) || has(self.credentialsSecretRef)",message="query requires credentialsSecretRef to be set"
+type PostgresProbe struct {
+	// database is the database named in the startup message.
+	// Defaults to "postgres".
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// sslMode controls TLS negotiation through the SSLRequest handshake.
+	// "disable" never uses TLS, "prefer" uses it when the server supports it and
+	// "require" fails when the server does not. As with libpq, the server
+	// certificate is not verified. Defaults to "prefer".
+	// +kubebuilder:validation:Enum=disable;prefer;require
+	// +optional
+	SSLMode string `json:"sslMode,omitempty"`
+
+	// credentialsSecretRef references the Secret holding the username and password
+	// used to authenticate. When omitted, the probe only performs the startup handshake
+	// and the server is ready as soon as it asks for authentication, so errors it only
+	// reports after authenticating, such as "too many clients already", are not detected.
+	// +optional
+	CredentialsSecretRef *SecretCredentialsRef `json:"credentialsSecretRef,omitempty"`
+
+	// query is the SQL statement run after authenticating.
+	// Defaults to "SELECT 1". Requires credentialsSecretRef.
+	// +optional
+	Query string `json:"query,omitempty"`
+}
+
+// MySQLProbe configures a probe that reads the initial handshake packet of a MySQL or
+// MariaDB server. The server version it announces is reported in the dependency's
+// status. An error packet sent instead of the handshake (e.g. "Too many connections")
+// keeps the dependency not ready. With credentials the probe additionally
+// authenticates and runs SELECT 1.
+type MySQLProbe struct {
+	// database is the default database requested when authenticating.
+	// Only meaningful when credentialsSecretRef is set.
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// credentialsSecretRef references the Secret holding the username and password
+	// used to authenticate. When omitted, the probe only reads the handshake.
+	// +optional
+	CredentialsSecretRef *SecretCredentialsRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// RedisCredentialsRef references a Secret in the BootDependency's namespace that holds
+// the password (and optionally the ACL username) a Redis probe authenticates with.
+type RedisCredentialsRef struct {
+	// name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// usernameKey is the key in the Secret that holds the ACL username.
+	// When omitted, the probe authenticates with the password only (AUTH <password>).
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// passwordKey is the key in the Secret that holds the password.
+	// Defaults to "password".
+	// +kubebuilder:default="password"
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// RedisProbe configures a probe that sends PING to a Redis server and requires PONG.
+// A server that is still loading its dataset answers -LOADING and is not ready.
+type RedisProbe struct {
+	// credentialsSecretRef references the Secret holding the credentials sent with
+	// AUTH before PING. When omitted, no AUTH command is sent.
+	// +optional
+	CredentialsSecretRef *RedisCredentialsRef `json:"credentialsSecretRef,omitempty"`
+
+	// requireMaster additionally requires INFO replication to report role:master,
+	// so that replicas and nodes in the middle of a failover are not ready.
+	// Defaults to false.
+	// +optional
+	RequireMaster bool `json:"requireMaster,omitempty"`
+}
+
+// KafkaProbe configures a probe that sends ApiVersions and Metadata requests to a
+// Kafka broker. The broker is only ready once it reports an active controller, so an
+// open port on a cluster whose controller quorum has not formed yet is not enough.
+type KafkaProbe struct {
+	// topics lists topics that must exist, with a leader for every partition,
+	// before the dependency is ready. Topics are never auto-created by the probe.
+	// +optional
+	Topics []string `json:"topics,omitempty"`
+}
+
+// AMQPProbe configures a probe that speaks AMQP 0-9-1 (e.g. RabbitMQ). The broker
+// is only ready once it answers the protocol header with Connection.Start, which
+// RabbitMQ does not do while it is still booting.
+type AMQPProbe struct {
+	// vhost is the virtual host opened after authenticating.
+	// Defaults to "/". Only meaningful when credentialsSecretRef is set.
+	// +optional
+	VHost string `json:"vhost,omitempty"`
+
+	// credentialsSecretRef references the Secret holding the username and password
+	// used to authenticate with the PLAIN mechanism. When set, the probe completes
+	// Connection.Start, Tune and Open against vhost. When omitted, the probe only
+	// waits for Connection.Start.
+	// +optional
+	CredentialsSecretRef *SecretCredentialsRef `json:"credentialsSecretRef,omitempty"`
+}
+
+// MongoDBProbe configures a probe that sends the hello command over OP_MSG to a
+// MongoDB server. Without further options any successful reply is ready; use
+// requireWritablePrimary to wait until a replica set has elected a primary.
+type MongoDBProbe struct {
+	// requireWritablePrimary requires the server to report isWritablePrimary, so that
+	// secondaries and replica sets that are still electing a primary are not ready.
+	// Defaults to false.
+	// +optional
+	RequireWritablePrimary bool `json:"requireWritablePrimary,omitempty"`
+
+	// replicaSet requires the server to be a member of the replica set with this name.
+	// +optional
+	ReplicaSet string `json:"replicaSet,omitempty"`
+}
+
+// DNSProbe configures a probe that waits for a DNS record instead of a network
+// endpoint, e.g. a managed database behind a private DNS zone. The dependency is only
+// considered ready once the record resolves and, when expected is set, the answer
+// contains every expected value.
+type DNSProbe struct {
+	// name is the record to look up. Defaults to the dependency's host, or the
+	// cluster DNS name of the Service.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// type is the record type to look up. Defaults to A.
+	// +kubebuilder:validation:Enum=A;AAAA;CNAME;SRV;TXT
+	// +kubebuilder:default="A"
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// expected lists values that must all be present in the answer: IP addresses for
+	// A and AAAA, the canonical name for CNAME, target:port for SRV and the record text
+	// for TXT. When omitted, any non-empty answer is accepted.
+	// +optional
+	Expected []string `json:"expected,omitempty"`
+
+	// resolver is the address of the DNS server to query, as host or host:port
+	// (port 53 when omitted). Defaults to the resolvers from /etc/resolv.conf.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Resolver string `json:"resolver,omitempty"`
+}
+
+// SecretKeySelector references a single key of a Secret in the BootDependency's
+// namespace.
+type SecretKeySelector struct {
+	// name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// key is the key in the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// ConfigMapKeySelector references a single key of a ConfigMap in the BootDependency's
+// namespace.
+type ConfigMapKeySelector struct {
+	// name is the name of the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// key is the key in the ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Key string `json:"key"`
+}
+
+// CABundleRef selects the key of a Secret or ConfigMap in the BootDependency's
+// namespace that holds PEM-encoded CA certificates. Exactly one of secretKeyRef or
+// configMapKeyRef must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.secretKeyRef) != has(self.configMapKeyRef)",message="exactly one of secretKeyRef or configMapKeyRef must be set"
+type CABundleRef struct {
+	// secretKeyRef selects a key of a Secret.
+	// +optional
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// configMapKeyRef selects a key of a ConfigMap, e.g. one distributed by trust-manager.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// ClientCertSecretRef references a Secret in the BootDependency's namespace that holds
+// the PEM-encoded client certificate and private key presented for mutual TLS, such as
+// a kubernetes.io/tls Secret issued by cert-manager.
+type ClientCertSecretRef struct {
+	// name is the name of the Secret.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// certKey is the key in the Secret that holds the certificate chain.
+	// Defaults to "tls.crt".
+	// +kubebuilder:default="tls.crt"
+	// +optional
+	CertKey string `json:"certKey,omitempty"`
+
+	// keyKey is the key in the Secret that holds the private key.
+	// Defaults to "tls.key".
+	// +kubebuilder:default="tls.key"
+	// +optional
+	KeyKey string `json:"keyKey,omitempty"`
+}
+
+// TLSProbe configures a probe that completes a TLS handshake without sending any
+// application data and then checks the certificate the server presented. Unless
+// insecure is set, the chain must verify against the system roots or caBundleRef and
+// the certificate must be valid for serverName.
+type TLSProbe struct {
+	// serverName is sent as SNI and used for hostname verification.
+	// Defaults to the dependency's host, or the FQDN of its Service, e.g.
+	// "ldap.directory.svc.cluster.local", in both the controller and the init container.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// subjectAltNames lists DNS names or IP addresses that must all be covered by the
+	// leaf certificate. Wildcard certificates match as in hostname verification.
+	// +optional
+	SubjectAltNames []string `json:"subjectAltNames,omitempty"`
+
+	// minRemainingValidity is the shortest time the leaf certificate may have left
+	// before it expires, e.g. "720h". A certificate that expires sooner is not ready.
+	// +kubebuilder:validation:Pattern=`^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+	// +optional
+	MinRemainingValidity string `json:"minRemainingValidity,omitempty"`
+
+	// issuer requires the leaf certificate's issuer to match, either its common name
+	// or its full distinguished name (e.g. "CN=R11,O=Let's Encrypt,C=US").
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// caBundleRef references PEM-encoded CA certificates, in a Secret or a ConfigMap,
+	// that the chain is verified against instead of the system roots.
+	// +optional
+	CABundleRef *CABundleRef `json:"caBundleRef,omitempty"`
+
+	// alpnProtocols lists the ALPN protocols offered in the handshake (e.g. h2).
+	// When set, the server must negotiate one of them.
+	// +optional
+	ALPNProtocols []string `json:"alpnProtocols,omitempty"`
+
+	// insecure skips chain and hostname verification. The other certificate
+	// assertions still apply. Defaults to false.
+	// +optional
+	Insecure bool `json:"insecure,omitempty"`
+}
+
+// WorkloadRef references a workload in the BootDependency's namespace whose rollout
+// must be complete.
+type WorkloadRef struct {
+	// kind is the kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	Kind string `json:"kind"`
+
+	// name is the name of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// JobRef references a Job in the BootDependency's namespace that must have completed
+// successfully. Exactly one of name or selector must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.name) != has(self.selector)",message="exactly one of name or selector must be set"
+type JobRef struct {
+	// name is the name of the Job.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// selector matches Jobs by label. The most recently created matching Job is used,
+	// so that a migration re-run under a new name is picked up automatically.
+	// +kubebuilder:validation:MinProperties=1
+	// +optional
+	Selector map[string]string `json:"selector,omitempty"`
+}
+
+// ResourceRef references any Kubernetes object and a CEL expression that must hold
+// for it, e.g. a cert-manager Certificate being Ready or a PersistentVolumeClaim being
+// Bound. Secrets cannot be referenced, and the validating webhook requires the author
+// of the BootDependency to be allowed to get the object.
+type ResourceRef struct {
+	// apiVersion is the group/version of the object, e.g. "cert-manager.io/v1" or "v1".
+	// +kubebuilder:validation:MinLength=1
+	APIVersion string `json:"apiVersion"`
+
+	// kind is the kind of the object, e.g. "Certificate".
+	// +kubebuilder:validation:MinLength=1
+	Kind string `json:"kind"`
+
+	// namespace of the object. Defaults to the BootDependency's namespace.
+	// Ignored for cluster-scoped kinds such as Namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// name is the name of the object.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// expression is a CEL expression over the object, available as `object`, that must
+	// evaluate to true for the dependency to be ready,
+	// e.g. `object.status.phase == "Bound"`.
+	// +kubebuilder:validation:MinLength=1
+	Expression string `json:"expression"`
+}
+
+// EndpointsProbe configures a check of the Service's EndpointSlices instead of a
+// connection to its cluster DNS name. A connection only proves that kube-proxy picked
+// some endpoint, whereas this check counts the endpoints that are ready to serve.
+type EndpointsProbe struct {
+	// minReady is the number of ready endpoints the Service must have.
+	// Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	// +optional
+	MinReady int32 `json:"minReady,omitempty"`
+
+	// perZone additionally requires at least one ready endpoint in every zone that
+	// the Service has endpoints in. Defaults to false.
+	// +optional
+	PerZone bool `json:"perZone,omitempty"`
+}
+
+// ProxySpec routes the probe of a dependency through an HTTP proxy, or connects
+// directly. Exactly one of url or disabled must be set.
+// +kubebuilder:validation:XValidation:rule="has(self.url) != (has(self.disabled) && self.disabled)",message="exactly one of url or disabled must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.credentialsSecretRef) || has(self.url)",message="credentialsSecretRef requires url to be set"
+type ProxySpec struct {
+	// url is the HTTP proxy, e.g. "http://proxy.corp.example:3128". HTTP probes send
+	// their request to the proxy and TCP probes open a tunnel with CONNECT. The URL is
+	// copied into the init containers of the workloads, so it must not contain user
+	// info: use credentialsSecretRef instead.
+	// +kubebuilder:validation:Pattern=`^https?://[^/@]+(/.*)?$`
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// credentialsSecretRef references the Secret holding the username and password
+	// sent to the proxy as Proxy-Authorization.
+	// +optional
+	CredentialsSecretRef *SecretCredentialsRef `json:"credentialsSecretRef,omitempty"`
+
+	// disabled connects directly, ignoring the operator-wide proxy.
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
 }
 
 // ServiceDependency defines a single dependency that must be reachable before the owner can start.
-// Exactly one of `service` or `host` must be specified.
+// Exactly one of `service`, `host`, `workloadRef`, `jobRef` or `resourceRef` must be specified.
+// +kubebuilder:validation:XValidation:rule="has(self.port) || has(self.portName) || has(self.dns) || has(self.endpoints) || has(self.workloadRef) || has(self.jobRef) || has(self.resourceRef)",message="port or portName is required unless dns, endpoints, workloadRef, jobRef or resourceRef is set"
+// +kubebuilder:validation:XValidation:rule="!has(self.port) || !has(self.portName)",message="only one of port or portName may be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.portName) || has(self.service)",message="portName requires service to be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.namespace) || has(self.service)",message="namespace requires service to be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.httpScheme) || has(self.httpPath)",message="httpScheme requires httpPath to be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.insecure) || !self.insecure || has(self.httpPath)",message="insecure requires httpPath to be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.caBundleRef) || (has(self.httpScheme) && self.httpScheme == 'https')",message="caBundleRef requires httpScheme to be https"
+// +kubebuilder:validation:XValidation:rule="!has(self.clientCertSecretRef) || (has(self.httpScheme) && self.httpScheme == 'https')",message="clientCertSecretRef requires httpScheme to be https"
 // +kubebuilder:validation:XValidation:rule="!has(self.httpMethod) || has(self.httpPath)",message="httpMethod requires httpPath to be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.httpHeaders) || size(self.httpHeaders) == 0 || has(self.httpPath)",message="httpHeaders requires httpPath to be set"
 // +kubebuilder:validation:XValidation:rule="!has(self.httpExpectedStatuses) || size(self.httpExpectedStatuses) == 0 || has(self.httpPath)",message="httpExpectedStatuses requires httpPath to be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.httpExpression) || has(self.httpPath)",message="httpExpression requires httpPath to be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.endpoints) || has(self.service)",message="endpoints requires service to be set"
+// +kubebuilder:validation:XValidation:rule="!(has(self.proxy) || (has(self.dualStack) && self.dualStack)) || !(has(self.grpc) || has(self.postgres) || has(self.mysql) || has(self.redis) || has(self.kafka) || has(self.amqp) || has(self.mongodb) || has(self.dns) || has(self.tls) || has(self.endpoints) || has(self.workloadRef) || has(self.jobRef) || has(self.resourceRef))",message="proxy and dualStack are only supported for TCP and HTTP probes"
+// +kubebuilder:validation:XValidation:rule="!has(self.dualStack) || !self.dualStack || !has(self.proxy) || !has(self.proxy.url)",message="dualStack cannot be combined with proxy.url"
+// +kubebuilder:validation:XValidation:rule="[has(self.httpPath), has(self.grpc), has(self.postgres), has(self.mysql), has(self.redis), has(self.kafka), has(self.amqp), has(self.mongodb), has(self.dns), has(self.tls), has(self.workloadRef), has(self.jobRef), has(self.resourceRef), has(self.endpoints)].filter(x, x).size() <= 1",message="only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"
 type ServiceDependency struct {
-	// service is the name of a Kubernetes Service in the same namespace to wait for.
-	// Mutually exclusive with host.
+	// service is the name of a Kubernetes Service to wait for, in namespace.
+	// Mutually exclusive with host, workloadRef, jobRef and resourceRef.
 	// +kubebuilder:validation:MinLength=1
 	// +optional
 	Service string `json:"service,omitempty"`
 
+	// namespace of the Service, e.g. a shared "platform" namespace. Defaults to the
+	// BootDependency's namespace. Requires service.
+	// +kubebuilder:validation:MinLength=1
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
 	// host is an external hostname or IP address to wait for.
 	// Use this for dependencies outside the cluster (e.g. a managed database, an external API).
-	// Mutually exclusive with service.
+	// It must be a lowercase DNS subdomain or an IP address; IPv6 addresses are
+	// written without brackets, e.g. 2001:db8::10.
+	// Mutually exclusive with service, workloadRef, jobRef and resourceRef.
 	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=253
+	// +kubebuilder:validation:Pattern=`^([a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*|[0-9A-Fa-f.]*:[0-9A-Fa-f:.]*)$`
+	// +kubebuilder:validation:XValidation:rule="!self.startsWith('[')",message="IPv6 addresses must not be enclosed in brackets"
 	// +optional
 	Host string `json:"host,omitempty"`
 
+	// workloadRef is a Deployment, StatefulSet or DaemonSet in the same namespace whose
+	// rollout must be complete: all desired replicas updated and available. Its status
+	// is read from the Kubernetes API instead of probing the network.
+	// Mutually exclusive with service, host, jobRef and resourceRef.
+	// +optional
+	WorkloadRef *WorkloadRef `json:"workloadRef,omitempty"`
+
+	// jobRef is a Job in the same namespace that must have completed successfully.
+	// A Job that has failed puts the BootDependency into a terminal failed state
+	// until the Job is replaced.
+	// Mutually exclusive with service, host, workloadRef and resourceRef.
+	// +optional
+	JobRef *JobRef `json:"jobRef,omitempty"`
+
+	// resourceRef is any Kubernetes object, identified by apiVersion, kind, namespace
+	// and name, together with a CEL expression that must be true for it.
+	// Mutually exclusive with service, host, workloadRef and jobRef.
+	// +optional
+	ResourceRef *ResourceRef `json:"resourceRef,omitempty"`
+
 	// port is the TCP port that must be open on the dependency.
+	// Required unless portName, dns, endpoints, workloadRef, jobRef or resourceRef is set.
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=65535
-	Port int32 `json:"port"`
+	// +optional
+	Port int32 `json:"port,omitempty"`
+
+	// portName is the name of a port of the Service, e.g. "http" or "grpc", to use
+	// instead of a numeric port. It is resolved from the Service spec when the
+	// dependency is probed. Requires service. Mutually exclusive with port.
+	// +kubebuilder:validation:MaxLength=15
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +optional
+	PortName string `json:"portName,omitempty"`
 
 	// httpPath is an optional HTTP(S) path to probe instead of a raw TCP check.
 	// When set, the controller and init container perform an HTTP GET to
@@ -77,6 +546,18 @@ type ServiceDependency struct {
 	// +optional
 	Insecure bool `json:"insecure,omitempty"`
 
+	// caBundleRef references PEM-encoded CA certificates that the server certificate of
+	// an HTTPS probe is verified against instead of the system roots.
+	// Only meaningful when httpScheme is "https".
+	// +optional
+	CABundleRef *CABundleRef `json:"caBundleRef,omitempty"`
+
+	// clientCertSecretRef references the client certificate and private key that an
+	// HTTPS probe presents to servers that require mutual TLS.
+	// Only meaningful when httpScheme is "https".
+	// +optional
+	ClientCertSecretRef *ClientCertSecretRef `json:"clientCertSecretRef,omitempty"`
+
 	// httpMethod is the HTTP verb to use when httpPath is set (e.g. GET, POST, HEAD).
 	// Must be an uppercase HTTP method name. Defaults to GET when omitted.
 	// Only meaningful when httpPath is set.
@@ -97,6 +578,98 @@ type ServiceDependency struct {
 	// +optional
 	HTTPExpectedStatuses []int32 `json:"httpExpectedStatuses,omitempty"`
 
+	// httpExpression is a CEL expression that must evaluate to true for the response to
+	// be considered healthy, in addition to the status code check. It can refer to
+	// status (int), headers (lower-case header names to comma-joined values) and body
+	// (the decoded JSON response, or the raw body as a string when it is not JSON),
+	// e.g. body.status == "UP" && body.db.ready.
+	// Only meaningful when httpPath is set.
+	// +optional
+	HTTPExpression string `json:"httpExpression,omitempty"`
+
+	// grpc switches the probe to the gRPC Health Checking Protocol.
+	// When set, the controller and init container call grpc.health.v1.Health/Check
+	// on {target}:{port} and wait until the response status is SERVING.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	GRPC *GRPCProbe `json:"grpc,omitempty"`
+
+	// postgres switches the probe to the PostgreSQL wire protocol.
+	// When set, the controller and init container perform the startup handshake
+	// (optionally authenticating and running a query) instead of a raw TCP check.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	Postgres *PostgresProbe `json:"postgres,omitempty"`
+
+	// mysql switches the probe to the MySQL client/server protocol.
+	// When set, the controller and init container read the server's handshake
+	// (optionally authenticating and running SELECT 1) instead of a raw TCP check.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	MySQL *MySQLProbe `json:"mysql,omitempty"`
+
+	// redis switches the probe to a Redis PING.
+	// When set, the controller and init container send PING (after AUTH when
+	// credentials are configured) and wait until the server answers PONG.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	Redis *RedisProbe `json:"redis,omitempty"`
+
+	// kafka switches the probe to Kafka broker metadata.
+	// When set, the controller and init container wait until the broker reports an
+	// active controller and, optionally, until the listed topics have leaders.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	Kafka *KafkaProbe `json:"kafka,omitempty"`
+
+	// amqp switches the probe to an AMQP 0-9-1 connection handshake.
+	// When set, the controller and init container exchange the protocol header and,
+	// with credentials, open a connection to the configured virtual host.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	AMQP *AMQPProbe `json:"amqp,omitempty"`
+
+	// mongodb switches the probe to the MongoDB hello command.
+	// When set, the controller and init container send hello over OP_MSG and wait
+	// until the reply satisfies the configured role requirements.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	MongoDB *MongoDBProbe `json:"mongodb,omitempty"`
+
+	// dns switches the probe to a DNS lookup of the dependency's record.
+	// When set, the controller and init container resolve the record and wait until
+	// the answer contains the expected values. port is not used.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	DNS *DNSProbe `json:"dns,omitempty"`
+
+	// tls switches the probe to a TLS handshake with certificate assertions.
+	// When set, the controller and init container complete a handshake on
+	// {target}:{port} without sending HTTP and check the presented certificate.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	TLS *TLSProbe `json:"tls,omitempty"`
+
+	// endpoints switches the probe to the Service's EndpointSlices.
+	// When set, the controller and init container count the ready endpoints of the
+	// Service instead of connecting to it. port is not used. Requires service.
+	// Mutually exclusive with httpPath and the other protocol probes.
+	// +optional
+	Endpoints *EndpointsProbe `json:"endpoints,omitempty"`
+
+	// proxy overrides the operator-wide HTTP proxy for this dependency's TCP or HTTP
+	// probe. The operator-wide proxy only applies to host dependencies whose host is
+	// not listed in the operator's no-proxy setting.
+	// +optional
+	Proxy *ProxySpec `json:"proxy,omitempty"`
+
+	// dualStack requires the dependency to be reachable over both IPv4 and IPv6: its
+	// name must resolve to A and AAAA records and the TCP or HTTP probe must succeed
+	// over each address family. Dual-stack probes always connect directly.
+	// Defaults to false.
+	// +optional
+	DualStack bool `json:"dualStack,omitempty"`
+
 	// timeout is how long to wait for this dependency before giving up.
 	// Defaults to 60s if not specified.
 	// +kubebuilder:default="60s"
@@ -104,14 +677,58 @@ type ServiceDependency struct {
 	Timeout string `json:"timeout,omitempty"`
 }
 
+// TargetRef identifies a workload in the BootDependency's namespace.
+type TargetRef struct {
+	// kind is the kind of the workload.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;ReplicaSet;Job;CronJob;Pod
+	Kind string `json:"kind"`
+
+	// name is the name of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
 // BootDependencySpec defines the desired state of BootDependency.
+// +kubebuilder:validation:XValidation:rule="!has(self.targetRef) || !has(self.selector)",message="only one of targetRef or selector may be set"
 type BootDependencySpec struct {
+	// targetKind is the kind of the workloads in this namespace that the init
+	// containers are injected into: the workload with the same name as the
+	// BootDependency, or those matched by selector. For a CronJob they are injected
+	// into its jobTemplate. Ignored when targetRef is set. Defaults to Deployment.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;ReplicaSet;Job;CronJob;Pod
+	// +kubebuilder:default=Deployment
+	// +optional
+	TargetKind string `json:"targetKind,omitempty"`
+
+	// targetRef names the workload to gate instead of the one with the same name as
+	// the BootDependency. Mutually exclusive with selector.
+	// +optional
+	TargetRef *TargetRef `json:"targetRef,omitempty"`
+
+	// selector gates every workload of kind targetKind in this namespace whose labels
+	// match, instead of the one with the same name as the BootDependency, so that one
+	// BootDependency can gate many workloads. Mutually exclusive with targetRef.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
 	// dependsOn is the list of services that must be reachable before the
-	// Deployment with the same name in this namespace is allowed to start.
+	// targeted workloads are allowed to start.
 	// +kubebuilder:validation:MinItems=1
 	DependsOn []ServiceDependency `json:"dependsOn"`
 }
 
+// TargetKindOrDefault returns the kind of the targeted workloads: the kind of
+// spec.targetRef, spec.targetKind, or Deployment when neither is set.
+func (s BootDependencySpec) TargetKindOrDefault() string {
+	if s.TargetRef != nil {
+		return s.TargetRef.Kind
+	}
+	if s.TargetKind == "" {
+		return "Deployment"
+	}
+	return s.TargetKind
+}
+
 // BootDependencyStatus defines the observed state of BootDependency.
 type BootDependencyStatus struct {
 	// conditions represent the current state of the BootDependency.
@@ -124,6 +741,39 @@ type BootDependencyStatus struct {
 	// are currently reachable, e.g. "2/3".
 	// +optional
 	ResolvedDependencies string `json:"resolvedDependencies,omitempty"`
+
+	// dependencies reports the result of the most recent probe for each entry in
+	// spec.dependsOn, in the same order.
+	// +listType=atomic
+	// +optional
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+
+	// targets lists the workloads the BootDependency currently gates.
+	// +listType=atomic
+	// +optional
+	Targets []TargetRef `json:"targets,omitempty"`
+}
+
+// DependencyStatus reports the result of the most recent probe of a single dependency.
+type DependencyStatus struct {
+	// name identifies the dependency as "<label>:<port>", with IPv6 hosts in brackets,
+	// "<label>:<portName>" for a named Service port, or "<label>" alone for DNS, workload,
+	// Job and resource dependencies, which have no port. The label is the host, the Service,
+	// "<namespace>/<service>" for a Service in another namespace, or "<kind>/<name>", e.g.
+	// "deployment/postgres" or "job/app=migrate".
+	Name string `json:"name"`
+
+	// ready is true when the most recent probe succeeded.
+	Ready bool `json:"ready"`
+
+	// reason is a CamelCase, machine-readable explanation of why the dependency
+	// is not ready, e.g. "DatabaseInRecovery" or "TooManyConnections".
+	// +optional
+	Reason string `json:"reason,omitempty"`
+
+	// message is a human-readable description of the most recent probe result.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,155 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterBootDependencySpec defines the desired state of ClusterBootDependency.
+type ClusterBootDependencySpec struct {
+	// namespaceSelector selects the namespaces whose workloads wait for the
+	// dependencies. Omit it to select every namespace.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// targetKind is the kind of the workloads the init containers are injected into.
+	// For a CronJob they are injected into its jobTemplate. Defaults to Deployment.
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;ReplicaSet;Job;CronJob;Pod
+	// +kubebuilder:default=Deployment
+	// +optional
+	TargetKind string `json:"targetKind,omitempty"`
+
+	// selector selects the workloads of kind targetKind in the selected namespaces
+	// whose labels match. Omit it to select every workload of that kind.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+
+	// dependsOn is the list of services that must be reachable before the selected
+	// workloads are allowed to start. A service without a namespace is looked up in
+	// the namespace of each workload, and Secrets and ConfigMaps are read from it.
+	// +kubebuilder:validation:MinItems=1
+	DependsOn []ServiceDependency `json:"dependsOn"`
+}
+
+// TargetKindOrDefault returns spec.targetKind, or Deployment when it is not set.
+func (s ClusterBootDependencySpec) TargetKindOrDefault() string {
+	if s.TargetKind == "" {
+		return "Deployment"
+	}
+	return s.TargetKind
+}
+
+// ClusterBootDependencyStatus defines the observed state of ClusterBootDependency.
+type ClusterBootDependencyStatus struct {
+	// conditions represent the current state of the ClusterBootDependency.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// readyNamespaces is a human-readable summary of how many selected namespaces
+	// have all dependencies reachable, e.g. "4/5".
+	// +optional
+	ReadyNamespaces string `json:"readyNamespaces,omitempty"`
+
+	// dependencies reports, for each entry in spec.dependsOn in the same order, how
+	// many of the selected namespaces it is reachable from.
+	// +listType=atomic
+	// +optional
+	Dependencies []ClusterDependencyStatus `json:"dependencies,omitempty"`
+
+	// failingNamespaces reports the dependencies that are not reachable from each
+	// selected namespace that is not ready, sorted by namespace. At most 20
+	// namespaces are listed; readyNamespaces counts all of them.
+	// +listType=map
+	// +listMapKey=namespace
+	// +kubebuilder:validation:MaxItems=20
+	// +optional
+	FailingNamespaces []NamespaceDependencyStatus `json:"failingNamespaces,omitempty"`
+}
+
+// ClusterDependencyStatus reports the readiness of one of a ClusterBootDependency's
+// dependencies across the selected namespaces.
+type ClusterDependencyStatus struct {
+	// name identifies the dependency, as in the status of a BootDependency.
+	Name string `json:"name"`
+
+	// ready is true when the dependency is reachable from every selected namespace.
+	Ready bool `json:"ready"`
+
+	// readyNamespaces is a human-readable summary of how many selected namespaces
+	// the dependency is reachable from, e.g. "4/5".
+	// +optional
+	ReadyNamespaces string `json:"readyNamespaces,omitempty"`
+}
+
+// NamespaceDependencyStatus reports the dependencies of a ClusterBootDependency that
+// are not reachable from one namespace.
+type NamespaceDependencyStatus struct {
+	// namespace is the name of the namespace.
+	Namespace string `json:"namespace"`
+
+	// resolvedDependencies is a human-readable summary of how many dependencies
+	// are currently reachable, e.g. "2/3".
+	// +optional
+	ResolvedDependencies string `json:"resolvedDependencies,omitempty"`
+
+	// dependencies reports the result of the most recent probe for each dependency
+	// that is not reachable from the namespace, in the order of spec.dependsOn.
+	// +listType=atomic
+	// +optional
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Namespaces",type="string",JSONPath=".status.readyNamespaces"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterBootDependency is the Schema for the clusterbootdependencies API. It declares
+// dependencies shared by the workloads of many namespaces.
+type ClusterBootDependency struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// metadata is a standard object metadata
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitzero"`
+
+	// spec defines the desired state of ClusterBootDependency
+	// +required
+	Spec ClusterBootDependencySpec `json:"spec"`
+
+	// status defines the observed state of ClusterBootDependency
+	// +optional
+	Status ClusterBootDependencyStatus `json:"status,omitzero"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterBootDependencyList contains a list of ClusterBootDependency
+type ClusterBootDependencyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitzero"`
+	Items           []ClusterBootDependency `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterBootDependency{}, &ClusterBootDependencyList{})
+}
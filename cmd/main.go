@@ -25,10 +25,12 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -37,6 +39,7 @@ import (
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
 	"github.com/user-cube/bootchain-operator/internal/controller"
+	"github.com/user-cube/bootchain-operator/internal/probe"
 	webhookv1 "github.com/user-cube/bootchain-operator/internal/webhook/v1"
 	webhookv1alpha1 "github.com/user-cube/bootchain-operator/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
@@ -64,7 +67,9 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var clusterDomain string
 	var tlsOpts []func(*tls.Config)
+	proxy := probe.ProxyFromEnvironment()
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -84,6 +89,18 @@ func main() {
 	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&proxy.HTTPProxy, "http-proxy", proxy.HTTPProxy,
+		"The HTTP proxy for HTTP probes of host dependencies. Defaults to the HTTP_PROXY environment variable.")
+	flag.StringVar(&proxy.HTTPSProxy, "https-proxy", proxy.HTTPSProxy,
+		"The HTTP proxy for HTTPS and TCP probes of host dependencies. Defaults to the HTTPS_PROXY environment variable.")
+	flag.StringVar(&proxy.NoProxy, "no-proxy", proxy.NoProxy,
+		"Comma-separated hosts, domains and CIDRs that are probed directly. Defaults to the NO_PROXY environment variable.")
+	flag.StringVar(&proxy.CredentialsSecret, "proxy-credentials-secret", "",
+		"The Secret, in each workload's namespace, with the username and password init containers authenticate "+
+			"to the proxy with. User info in the proxy URLs is never copied into pod specs.")
+	flag.StringVar(&clusterDomain, "cluster-domain", "",
+		"The DNS domain of the cluster used to build Service hostnames. "+
+			"Detected from the search path in /etc/resolv.conf when empty, falling back to cluster.local.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -92,6 +109,22 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	if err := proxy.Validate(); err != nil {
+		setupLog.Error(err, "Invalid proxy configuration")
+		os.Exit(1)
+	}
+
+	if clusterDomain == "" {
+		detected, err := probe.DetectClusterDomain(probe.ResolvConfPath)
+		if err != nil {
+			setupLog.Info("Could not detect the cluster domain, using the default",
+				"clusterDomain", probe.DefaultClusterDomain, "reason", err.Error())
+			detected = probe.DefaultClusterDomain
+		}
+		clusterDomain = detected
+	}
+	setupLog.Info("Using cluster domain", "clusterDomain", clusterDomain)
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -167,6 +200,11 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "cc29a6a6.bootchain-operator.ruicoelho.dev",
+		// Probes read single Secret and ConfigMap keys. Reading them from the API server
+		// avoids caching every Secret and ConfigMap in the cluster.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}, &corev1.ConfigMap{}}},
+		},
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
 		// Manager is stopped, otherwise, this setting is unsafe. Setting this significantly
@@ -185,17 +223,29 @@ func main() {
 	}
 
 	if err := (&controller.BootDependencyReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("bootdependency-controller"), //nolint:staticcheck
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("bootdependency-controller"), //nolint:staticcheck
+		Proxy:         proxy,
+		ClusterDomain: clusterDomain,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "Failed to create controller", "controller", "BootDependency")
 		os.Exit(1)
 	}
+	if err := (&controller.ClusterBootDependencyReconciler{
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("clusterbootdependency-controller"), //nolint:staticcheck
+		Proxy:         proxy,
+		ClusterDomain: clusterDomain,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "Failed to create controller", "controller", "ClusterBootDependency")
+		os.Exit(1)
+	}
 	// nolint:goconst
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
-		if err := webhookv1.SetupDeploymentWebhookWithManager(mgr); err != nil {
-			setupLog.Error(err, "Failed to create webhook", "webhook", "Deployment")
+		if err := webhookv1.SetupWorkloadWebhooksWithManager(mgr, proxy, clusterDomain); err != nil {
+			setupLog.Error(err, "Failed to create webhook", "webhook", "Workload")
 			os.Exit(1)
 		}
 	}
@@ -206,6 +256,13 @@ func main() {
 			os.Exit(1)
 		}
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookv1alpha1.SetupClusterBootDependencyWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "Failed to create webhook", "webhook", "ClusterBootDependency")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -0,0 +1,67 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command bootchain-probe performs a single readiness check for the dependency
+// described in its environment and exits 0 when the dependency is ready.
+// It is shipped in the minimal-tools image and invoked in a loop by the
+// wait-for-* init containers that the mutating webhook injects.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/user-cube/bootchain-operator/internal/probe"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run performs the probe and returns the process exit code: 0 when the dependency
+// is ready, 1 when it is not, and 2 when the environment is misconfigured.
+func run() int {
+	var timeout time.Duration
+	flag.DurationVar(&timeout, "timeout", 3*time.Second, "How long a single probe attempt may take.")
+	flag.Parse()
+
+	dep, err := probe.UnmarshalDependency(os.Getenv(probe.DependencyEnv))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", probe.DependencyEnv, err)
+		return 2
+	}
+	target := os.Getenv(probe.TargetEnv)
+	if target == "" {
+		fmt.Fprintf(os.Stderr, "%s is not set\n", probe.TargetEnv)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	detail, err := probe.Run(ctx, target, dep, probe.EnvSecrets{}, &probe.InClusterObjects{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s not ready: %v\n", target, err)
+		return 1
+	}
+	if detail != "" {
+		fmt.Printf("%s: %s\n", target, detail)
+	}
+	return 0
+}
@@ -55,6 +55,96 @@ webhooks:
     namespaceSelector:
       matchLabels:
         bootchain-webhook: enabled
+  - name: mstatefulset-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate-apps-v1-statefulset"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["apps"]
+        apiVersions: ["v1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["statefulsets"]
+    failurePolicy: Fail
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
+  - name: mdaemonset-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate-apps-v1-daemonset"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["apps"]
+        apiVersions: ["v1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["daemonsets"]
+    failurePolicy: Fail
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
+  - name: mreplicaset-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate-apps-v1-replicaset"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["apps"]
+        apiVersions: ["v1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["replicasets"]
+    failurePolicy: Ignore
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
+  - name: mjob-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate-batch-v1-job"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["batch"]
+        apiVersions: ["v1"]
+        operations: ["CREATE"]
+        resources: ["jobs"]
+    failurePolicy: Fail
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
+  - name: mcronjob-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate-batch-v1-cronjob"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["batch"]
+        apiVersions: ["v1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["cronjobs"]
+    failurePolicy: Fail
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
+  - name: mpod-v1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/mutate--v1-pod"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: [""]
+        apiVersions: ["v1"]
+        operations: ["CREATE"]
+        resources: ["pods"]
+    failurePolicy: Ignore
+    sideEffects: None
+    namespaceSelector:
+      matchLabels:
+        bootchain-webhook: enabled
 EOF
 
 kubectl apply -f - <<EOF
@@ -78,6 +168,18 @@ webhooks:
     namespaceSelector:
       matchLabels:
         bootchain-webhook: enabled
+  - name: vclusterbootdependency-v1alpha1.kb.io
+    admissionReviewVersions: ["v1"]
+    clientConfig:
+      url: "https://${HOST_IP}:${WEBHOOK_PORT}/validate-core-bootchain-operator-ruicoelho-dev-v1alpha1-clusterbootdependency"
+      caBundle: "${CA_BUNDLE}"
+    rules:
+      - apiGroups: ["core.bootchain-operator.ruicoelho.dev"]
+        apiVersions: ["v1alpha1"]
+        operations: ["CREATE", "UPDATE"]
+        resources: ["clusterbootdependencies"]
+    failurePolicy: Fail
+    sideEffects: None
 EOF
 
 echo ""
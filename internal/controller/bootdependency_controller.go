@@ -18,44 +18,84 @@ package controller
 
 import (
 	"context"
-	"crypto/tls"
 	"fmt"
 	"net"
-	"net/http"
 	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
 )
 
 const (
 	conditionReady       = "Ready"
 	requeueAfterReady    = 30 * time.Second
 	requeueAfterNotReady = 10 * time.Second
-	dialTimeout          = 3 * time.Second
-	httpTimeout          = 3 * time.Second
+	probeTimeout         = 3 * time.Second
 )
 
+// targetAPIVersions maps the workload kinds a BootDependency can target to their API versions.
+var targetAPIVersions = map[string]string{
+	"Deployment":  "apps/v1",
+	"StatefulSet": "apps/v1",
+	"DaemonSet":   "apps/v1",
+	"ReplicaSet":  "apps/v1",
+	"Job":         "batch/v1",
+	"CronJob":     "batch/v1",
+	"Pod":         "v1",
+}
+
 // BootDependencyReconciler reconciles a BootDependency object
 type BootDependencyReconciler struct {
 	client.Client
 	Scheme   *runtime.Scheme
 	Recorder record.EventRecorder
+	// Proxy is the operator-wide proxy for probes of host dependencies.
+	Proxy probe.Proxy
+	// ClusterDomain is the DNS domain of the cluster used to build Service hostnames.
+	// Defaults to cluster.local.
+	ClusterDomain string
+
+	// resources watches the kinds referenced by resourceRef dependencies. It is set up
+	// by SetupWithManager.
+	resources resourceWatcher
 }
 
 // +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=bootdependencies,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=bootdependencies/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=bootdependencies/finalizers,verbs=update
 // +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets;daemonsets;replicasets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 
 func (r *BootDependencyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -69,63 +109,38 @@ func (r *BootDependencyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	resolved := 0
 	total := len(bd.Spec.DependsOn)
 	allReady := true
+	// failed holds the first terminal failure, such as a failed Job. Probing again
+	// cannot resolve it, so polling stops unless another dependency is still pending.
+	var failed *corev1alpha1.DependencyStatus
+	pending := false
+
+	statuses := make([]corev1alpha1.DependencyStatus, 0, total)
 
-	secureClient := &http.Client{Timeout: httpTimeout}
-	insecureClient := &http.Client{
-		Timeout: httpTimeout,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
-		},
-	}
 	for _, dep := range bd.Spec.DependsOn {
 		label := depLabel(dep)
-		var checkErr error
-		if dep.HTTPPath != "" {
-			scheme := dep.HTTPScheme
-			if scheme == "" {
-				scheme = "http"
-			}
-			url := fmt.Sprintf("%s://%s:%d%s", scheme, depHost(dep, bd.Namespace), dep.Port, dep.HTTPPath)
-			httpClient := secureClient
-			if dep.Insecure {
-				httpClient = insecureClient
-			}
-			method := dep.HTTPMethod
-			if method == "" {
-				method = http.MethodGet
-			}
-			req, reqErr := http.NewRequest(method, url, nil) //nolint:noctx
-			if reqErr != nil {
-				checkErr = reqErr
-			} else {
-				for _, h := range dep.HTTPHeaders {
-					req.Header.Set(h.Name, h.Value)
-				}
-				resp, err := httpClient.Do(req)
-				if err != nil {
-					checkErr = err
-				} else {
-					_ = resp.Body.Close()
-					if !statusAccepted(resp.StatusCode, dep.HTTPExpectedStatuses) {
-						checkErr = fmt.Errorf("HTTP %d", resp.StatusCode)
-					}
-				}
-			}
-		} else {
-			addr := depAddress(dep, req.Namespace)
-			conn, err := net.DialTimeout("tcp", addr, dialTimeout)
-			if err != nil {
-				checkErr = err
-			} else {
-				_ = conn.Close()
+		if dep.ResourceRef != nil {
+			if err := r.resources.watch(dep.ResourceRef); err != nil {
+				log.Error(err, "Failed to watch resource kind", "dependency", label)
 			}
 		}
-
+		depStatus, checkErr := probeDependency(ctx, r.Client, r.Proxy, r.ClusterDomain, bd.Namespace, dep)
+		statuses = append(statuses, depStatus)
 		if checkErr != nil {
-			log.Info("Dependency not reachable", "dependency", label, "port", dep.Port, "error", checkErr)
-			r.Recorder.Eventf(&bd, corev1.EventTypeWarning, "DependencyNotReady",
-				"Dependency %s:%d is not reachable", label, dep.Port)
 			allReady = false
+			if probe.IsTerminal(checkErr) {
+				log.Info("Dependency failed", "dependency", label, "reason", depStatus.Reason, "error", checkErr)
+				r.Recorder.Eventf(&bd, corev1.EventTypeWarning, "DependencyFailed",
+					"Dependency %s failed: %s", depStatus.Name, depStatus.Message)
+				if failed == nil {
+					failed = &depStatus
+				}
+				continue
+			}
+			pending = true
+			log.Info("Dependency not reachable", "dependency", label, "port", dep.Port,
+				"reason", depStatus.Reason, "error", checkErr)
+			r.Recorder.Eventf(&bd, corev1.EventTypeWarning, "DependencyNotReady",
+				"Dependency %s is not reachable (%s)", depStatus.Name, depStatus.Reason)
 			continue
 		}
 		resolved++
@@ -137,14 +152,25 @@ func (r *BootDependencyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 
 	patch := client.MergeFrom(bd.DeepCopy())
 	bd.Status.ResolvedDependencies = fmt.Sprintf("%d/%d", resolved, total)
+	bd.Status.Dependencies = statuses
+	if targets, err := r.matchTargets(ctx, &bd); err != nil {
+		log.Error(err, "Failed to list the targeted workloads")
+	} else {
+		bd.Status.Targets = targets
+	}
 
 	var condStatus metav1.ConditionStatus
 	var reason, message string
-	if allReady {
+	switch {
+	case allReady:
 		condStatus = metav1.ConditionTrue
 		reason = "AllDependenciesReady"
 		message = fmt.Sprintf("All %d dependencies are reachable", total)
-	} else {
+	case failed != nil:
+		condStatus = metav1.ConditionFalse
+		reason = "DependencyFailed"
+		message = fmt.Sprintf("Dependency %s failed: %s", failed.Name, failed.Message)
+	default:
 		condStatus = metav1.ConditionFalse
 		reason = "DependenciesNotReady"
 		message = fmt.Sprintf("%d/%d dependencies are reachable", resolved, total)
@@ -173,48 +199,349 @@ func (r *BootDependencyReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 			"All %d dependencies are reachable", total)
 		return ctrl.Result{RequeueAfter: requeueAfterReady}, nil
 	}
+	if !pending {
+		// Terminal: the Job watch requeues once the failed Job is replaced.
+		return ctrl.Result{}, nil
+	}
 
 	return ctrl.Result{RequeueAfter: requeueAfterNotReady}, nil
 }
 
-// depAddress returns the dial address (host:port) for a dependency.
-// For in-cluster services it resolves to the FQDN; for external hosts it uses the host directly.
-func depAddress(dep corev1alpha1.ServiceDependency, namespace string) string {
-	return fmt.Sprintf("%s:%d", depHost(dep, namespace), dep.Port)
+// probeDependency probes dep as declared in namespace and returns its status along
+// with the probe error, if any. Secrets, ConfigMaps and referenced objects are read
+// from namespace, and so is a Service without a namespace of its own.
+func probeDependency(ctx context.Context, c client.Client, proxy probe.Proxy, clusterDomain, namespace string, dep corev1alpha1.ServiceDependency) (corev1alpha1.DependencyStatus, error) {
+	probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	reader := namespaceReader{reader: c, namespace: namespace}
+	host := probe.DependencyHost(dep, namespace, clusterDomain)
+	detail, err := probe.Run(probeCtx, host, proxy.Apply(host, dep), reader, reader)
+
+	status := corev1alpha1.DependencyStatus{
+		Name:    depName(dep),
+		Ready:   err == nil,
+		Message: detail,
+	}
+	if err != nil {
+		status.Reason = probe.Reason(err)
+		status.Message = err.Error()
+	}
+	return status, err
 }
 
-// depHost returns the hostname for a dependency.
-// For in-cluster services it builds the FQDN <service>.<namespace>.svc.cluster.local so that
-// the controller — which runs in a different namespace — can always resolve the service correctly.
-// For external dependencies (host field set) it returns the host directly.
-func depHost(dep corev1alpha1.ServiceDependency, namespace string) string {
-	if dep.Host != "" {
-		return dep.Host
+// matchTargets returns the workloads bd gates, sorted by name: the one named by its
+// targetRef or with its own name if it exists, or those of kind targetKind whose labels
+// match its selector. Only the workloads' metadata is read.
+func (r *BootDependencyReconciler) matchTargets(ctx context.Context, bd *corev1alpha1.BootDependency) ([]corev1alpha1.TargetRef, error) {
+	kind := bd.Spec.TargetKindOrDefault()
+	gvk := schema.FromAPIVersionAndKind(targetAPIVersions[kind], kind)
+
+	if bd.Spec.Selector == nil {
+		name := bd.Name
+		if bd.Spec.TargetRef != nil {
+			name = bd.Spec.TargetRef.Name
+		}
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		if err := r.Get(ctx, types.NamespacedName{Namespace: bd.Namespace, Name: name}, obj); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		return []corev1alpha1.TargetRef{{Kind: kind, Name: name}}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(bd.Spec.Selector)
+	if err != nil {
+		return nil, err
+	}
+	list := &metav1.PartialObjectMetadataList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(kind + "List"))
+	if err := r.List(ctx, list, client.InNamespace(bd.Namespace),
+		client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	targets := make([]corev1alpha1.TargetRef, 0, len(list.Items))
+	for _, item := range list.Items {
+		targets = append(targets, corev1alpha1.TargetRef{Kind: kind, Name: item.Name})
 	}
-	return fmt.Sprintf("%s.%s.svc.cluster.local", dep.Service, namespace)
+	slices.SortFunc(targets, func(a, b corev1alpha1.TargetRef) int { return strings.Compare(a.Name, b.Name) })
+	return targets, nil
 }
 
-// statusAccepted returns true when code is in the accepted list.
-// When the list is empty it falls back to the 2xx range (200–299).
-func statusAccepted(code int, accepted []int32) bool {
-	if len(accepted) == 0 {
-		return code >= 200 && code < 300
+// serviceNamespace returns the namespace of a service dependency: its namespace field
+// when set, otherwise namespace, the BootDependency's.
+func serviceNamespace(dep corev1alpha1.ServiceDependency, namespace string) string {
+	if dep.Namespace != "" {
+		return dep.Namespace
 	}
-	return slices.Contains(accepted, int32(code))
+	return namespace
+}
+
+// namespaceReader resolves Secret keys and objects referenced by probes from the
+// BootDependency's namespace. Reads go through the manager's client, which bypasses
+// the cache for Secrets and ConfigMaps so that they are not watched cluster-wide.
+type namespaceReader struct {
+	reader    client.Reader
+	namespace string
+}
+
+// GetObject implements probe.ObjectReader.
+func (s namespaceReader) GetObject(ctx context.Context, name string, obj client.Object) error {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	return s.reader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj)
+}
+
+// ListObjects implements probe.ObjectReader.
+func (s namespaceReader) ListObjects(ctx context.Context, namespace string, list client.ObjectList, selector labels.Selector) error {
+	if namespace == "" {
+		namespace = s.namespace
+	}
+	return s.reader.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+}
+
+// ReadSecretKey implements probe.SecretReader.
+func (s namespaceReader) ReadSecretKey(ctx context.Context, name, key string) (string, error) {
+	var secret corev1.Secret
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, &secret); err != nil {
+		return "", fmt.Errorf("failed to get Secret %s/%s: %w", s.namespace, name, err)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", fmt.Errorf("secret %s/%s has no key %q", s.namespace, name, key)
+	}
+	return string(value), nil
+}
+
+// ReadConfigMapKey implements probe.SecretReader.
+func (s namespaceReader) ReadConfigMapKey(ctx context.Context, name, key string) (string, error) {
+	var cm corev1.ConfigMap
+	if err := s.reader.Get(ctx, types.NamespacedName{Namespace: s.namespace, Name: name}, &cm); err != nil {
+		return "", fmt.Errorf("failed to get ConfigMap %s/%s: %w", s.namespace, name, err)
+	}
+	value, ok := cm.Data[key]
+	if !ok {
+		return "", fmt.Errorf("configmap %s/%s has no key %q", s.namespace, name, key)
+	}
+	return value, nil
 }
 
 // depLabel returns a human-readable identifier for a dependency (for logs and events).
+// Workloads and other resources are identified as kind/name, e.g. deployment/postgres,
+// and Jobs as job/name or job/selector, e.g. job/app=migrate. Services in another
+// namespace are identified as namespace/service, e.g. platform/kafka.
 func depLabel(dep corev1alpha1.ServiceDependency) string {
+	if dep.WorkloadRef != nil {
+		return strings.ToLower(dep.WorkloadRef.Kind) + "/" + dep.WorkloadRef.Name
+	}
+	if dep.JobRef != nil {
+		if dep.JobRef.Name != "" {
+			return "job/" + dep.JobRef.Name
+		}
+		return "job/" + labels.SelectorFromSet(dep.JobRef.Selector).String()
+	}
+	if dep.ResourceRef != nil {
+		return strings.ToLower(dep.ResourceRef.Kind) + "/" + dep.ResourceRef.Name
+	}
 	if dep.Host != "" {
 		return dep.Host
 	}
+	if dep.Namespace != "" {
+		return dep.Namespace + "/" + dep.Service
+	}
 	return dep.Service
 }
 
+// depName identifies a dependency in status and events as label:port, label:portName
+// for named Service ports, or by its label alone for DNS, workload, Job and resource
+// dependencies, which have no port.
+func depName(dep corev1alpha1.ServiceDependency) string {
+	if dep.PortName != "" {
+		return net.JoinHostPort(depLabel(dep), dep.PortName)
+	}
+	if dep.Port == 0 {
+		return depLabel(dep)
+	}
+	// JoinHostPort brackets IPv6 hosts, e.g. [2001:db8::10]:5432.
+	return net.JoinHostPort(depLabel(dep), strconv.Itoa(int(dep.Port)))
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *BootDependencyReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&corev1alpha1.BootDependency{}).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.requestsForJob)).
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.requestsForEndpointSlice)).
 		Named("bootdependency").
-		Complete(r)
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.resources = resourceWatcher{
+		controller: c,
+		cache:      mgr.GetCache(),
+		handler:    handler.EnqueueRequestsFromMapFunc(r.requestsForResource),
+	}
+	return nil
+}
+
+// resourceWatcher watches the kinds referenced by resourceRef dependencies, which are
+// only known at runtime.
+type resourceWatcher struct {
+	controller controller.Controller
+	cache      cache.Cache
+	handler    handler.EventHandler
+
+	mu      sync.Mutex
+	watched map[schema.GroupVersionKind]bool
+}
+
+// watch starts watching the kind of a resourceRef the first time it is seen, so that
+// a change to the object triggers a reconciliation instead of waiting for the next
+// requeue. Watches are kept for the lifetime of the manager. The operator needs list
+// and watch permission on the kind for the watch to sync.
+func (w *resourceWatcher) watch(ref *corev1alpha1.ResourceRef) error {
+	if w.controller == nil {
+		return nil
+	}
+	gvk := schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.watched[gvk] {
+		return nil
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	if err := w.controller.Watch(source.Kind[client.Object](w.cache, obj, w.handler)); err != nil {
+		return err
+	}
+	if w.watched == nil {
+		w.watched = make(map[schema.GroupVersionKind]bool)
+	}
+	w.watched[gvk] = true
+	return nil
+}
+
+// requestsForResource maps an event on a watched object to the BootDependencies whose
+// resourceRef points at it.
+func (r *BootDependencyReconciler) requestsForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list corev1alpha1.BootDependencyList
+	if err := r.List(ctx, &list); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list BootDependencies for resource", "name", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, bd := range list.Items {
+		if referencesResource(bd.Spec.DependsOn, bd.Namespace, obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: bd.Namespace, Name: bd.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// requestsForJob maps a Job event to the BootDependencies in its namespace that
+// reference it by name or selector, so that a completed or replaced Job is picked up
+// without waiting for the next poll.
+func (r *BootDependencyReconciler) requestsForJob(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list corev1alpha1.BootDependencyList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetNamespace())); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list BootDependencies for Job", "job", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, bd := range list.Items {
+		if referencesJob(bd.Spec.DependsOn, obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: bd.Namespace, Name: bd.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// requestsForEndpointSlice maps an EndpointSlice event to the BootDependencies that
+// depend on the slice's Service, in its namespace or from another one, so that a Service
+// gaining or losing endpoints is picked up without waiting for the next poll.
+func (r *BootDependencyReconciler) requestsForEndpointSlice(ctx context.Context, obj client.Object) []reconcile.Request {
+	service := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if service == "" {
+		return nil
+	}
+	var list corev1alpha1.BootDependencyList
+	if err := r.List(ctx, &list); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list BootDependencies for EndpointSlice", "service", service)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, bd := range list.Items {
+		if referencesEndpointSlice(bd.Spec.DependsOn, bd.Namespace, obj) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: bd.Namespace, Name: bd.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// referencesResource reports whether a resourceRef of deps, declared in namespace,
+// points at obj. An empty namespace matches objects in any namespace, as the
+// dependencies of a ClusterBootDependency are probed from many.
+func referencesResource(deps []corev1alpha1.ServiceDependency, namespace string, obj client.Object) bool {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	for _, dep := range deps {
+		ref := dep.ResourceRef
+		if ref == nil || ref.Name != obj.GetName() || schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind) != gvk {
+			continue
+		}
+		refNamespace := ref.Namespace
+		if refNamespace == "" {
+			refNamespace = namespace
+		}
+		// Cluster-scoped objects have no namespace and match by name alone.
+		if obj.GetNamespace() == "" || refNamespace == "" || obj.GetNamespace() == refNamespace {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesJob reports whether a jobRef of deps names the Job obj or selects it by
+// its labels. The caller checks the namespace.
+func referencesJob(deps []corev1alpha1.ServiceDependency, obj client.Object) bool {
+	for _, dep := range deps {
+		if dep.JobRef == nil {
+			continue
+		}
+		if dep.JobRef.Name == obj.GetName() ||
+			(dep.JobRef.Name == "" && labels.SelectorFromSet(dep.JobRef.Selector).Matches(labels.Set(obj.GetLabels()))) {
+			return true
+		}
+	}
+	return false
+}
+
+// referencesEndpointSlice reports whether deps, declared in namespace, depend on the
+// Service that the EndpointSlice obj belongs to. An empty namespace matches a Service
+// without a namespace of its own in any namespace.
+func referencesEndpointSlice(deps []corev1alpha1.ServiceDependency, namespace string, obj client.Object) bool {
+	service := obj.GetLabels()[discoveryv1.LabelServiceName]
+	for _, dep := range deps {
+		if dep.Service != service {
+			continue
+		}
+		if ns := serviceNamespace(dep, namespace); ns == "" || ns == obj.GetNamespace() {
+			return true
+		}
+	}
+	return false
 }
@@ -18,6 +18,7 @@ package controller
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -25,14 +26,23 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
 )
 
 var _ = Describe("BootDependency Controller", func() {
@@ -109,6 +119,57 @@ var _ = Describe("BootDependency Controller", func() {
 		})
 	})
 
+	Context("Targeted workloads", func() {
+		ctx := context.Background()
+
+		createDeployment := func(name string, labels map[string]string) {
+			deploy := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+				Spec: appsv1.DeploymentSpec{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}},
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": name}},
+						Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, deploy)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, deploy)
+		}
+
+		It("should list the workloads matched by the selector in status", func() {
+			createDeployment("kafka-consumer", map[string]string{"uses": "kafka"})
+			createDeployment("kafka-producer", map[string]string{"uses": "kafka"})
+			createDeployment("frontend", nil)
+
+			nn := types.NamespacedName{Name: "kafka-clients", Namespace: "default"}
+			resource := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+				Spec: corev1alpha1.BootDependencySpec{
+					Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"uses": "kafka"}},
+					DependsOn: []corev1alpha1.ServiceDependency{{Service: "kafka", Port: 9092}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, resource)
+
+			reconciler := &BootDependencyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &corev1alpha1.BootDependency{}
+			Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+			Expect(updated.Status.Targets).To(Equal([]corev1alpha1.TargetRef{
+				{Kind: "Deployment", Name: "kafka-consumer"},
+				{Kind: "Deployment", Name: "kafka-producer"},
+			}))
+		})
+	})
+
 	Context("HTTP health check", func() {
 		// parseTestServer extracts host and port from an httptest.Server URL.
 		// Works for both http:// and https:// URLs.
@@ -195,16 +256,33 @@ var _ = Describe("BootDependency Controller", func() {
 		})
 
 		It("should build FQDN from service name and BootDependency namespace for HTTP probes", func() {
-			// Unit-test depHost directly to guard against the regression where service-based
+			// Unit-test probe.DependencyHost directly to guard against the regression where service-based
 			// HTTP probes used the bare service name instead of the FQDN, causing DNS lookup
 			// failures when the controller runs in a different namespace than the target service.
 			dep := corev1alpha1.ServiceDependency{Service: "my-svc", Port: 8080}
-			Expect(depHost(dep, "my-namespace")).To(Equal("my-svc.my-namespace.svc.cluster.local"))
+			Expect(probe.DependencyHost(dep, "my-namespace", "")).To(Equal("my-svc.my-namespace.svc.cluster.local"))
+		})
+
+		It("should build the FQDN in the dependency's namespace when it is set", func() {
+			dep := corev1alpha1.ServiceDependency{Service: "kafka", Namespace: "platform", Port: 9092}
+			Expect(probe.DependencyHost(dep, "my-namespace", "")).To(Equal("kafka.platform.svc.cluster.local"))
+			Expect(depName(dep)).To(Equal("platform/kafka:9092"))
+		})
+
+		It("should build the FQDN with the configured cluster domain", func() {
+			dep := corev1alpha1.ServiceDependency{Service: "my-svc", Port: 8080}
+			Expect(probe.DependencyHost(dep, "my-namespace", "corp.internal")).To(Equal("my-svc.my-namespace.svc.corp.internal"))
 		})
 
 		It("should use the host field directly when set, not build a FQDN", func() {
 			dep := corev1alpha1.ServiceDependency{Host: "external.example.com", Port: 443}
-			Expect(depHost(dep, "any-namespace")).To(Equal("external.example.com"))
+			Expect(probe.DependencyHost(dep, "any-namespace", "")).To(Equal("external.example.com"))
+		})
+
+		It("should bracket IPv6 hosts in dependency names", func() {
+			dep := corev1alpha1.ServiceDependency{Host: "2001:db8::10", Port: 5432}
+			Expect(probe.DependencyHost(dep, "any-namespace", "")).To(Equal("2001:db8::10"))
+			Expect(depName(dep)).To(Equal("[2001:db8::10]:5432"))
 		})
 
 		It("should resolve an HTTPS dependency when insecure=true and server has a self-signed cert", func() {
@@ -327,5 +405,202 @@ var _ = Describe("BootDependency Controller", func() {
 			})
 			Expect(updated.Status.ResolvedDependencies).To(Equal("1/1"))
 		})
+
+		It("should report per-dependency status with a reason for each failing dependency", func() {
+			okSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			DeferCleanup(okSrv.Close)
+			failSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}))
+			DeferCleanup(failSrv.Close)
+			okHost, okPort := parseTestServer(okSrv)
+			failHost, failPort := parseTestServer(failSrv)
+
+			updated := createAndReconcile("http-per-dependency-status", []corev1alpha1.ServiceDependency{
+				{Host: okHost, Port: okPort, HTTPPath: "/healthz"},
+				{Host: failHost, Port: failPort, HTTPPath: "/healthz"},
+			})
+			Expect(updated.Status.Dependencies).To(HaveLen(2))
+			Expect(updated.Status.Dependencies[0].Ready).To(BeTrue())
+			Expect(updated.Status.Dependencies[0].Reason).To(BeEmpty())
+			Expect(updated.Status.Dependencies[1].Ready).To(BeFalse())
+			Expect(updated.Status.Dependencies[1].Reason).To(Equal(probe.ReasonUnexpectedStatus))
+			Expect(updated.Status.Dependencies[1].Message).To(ContainSubstring("HTTP 503"))
+		})
+	})
+
+	Context("gRPC health check", func() {
+		// startHealthServer serves the standard gRPC health service on a random local port.
+		startHealthServer := func() (*health.Server, string, int32) {
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			srv := grpc.NewServer()
+			hs := health.NewServer()
+			healthpb.RegisterHealthServer(srv, hs)
+			go func() { _ = srv.Serve(lis) }()
+			DeferCleanup(srv.Stop)
+			addr := lis.Addr().(*net.TCPAddr)
+			return hs, addr.IP.String(), int32(addr.Port)
+		}
+
+		reconcileDeps := func(resName string, deps []corev1alpha1.ServiceDependency) *corev1alpha1.BootDependency {
+			nn := types.NamespacedName{Name: resName, Namespace: "default"}
+			resource := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: resName, Namespace: "default"},
+				Spec:       corev1alpha1.BootDependencySpec{DependsOn: deps},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() {
+				r := &corev1alpha1.BootDependency{}
+				if err := k8sClient.Get(ctx, nn, r); err == nil {
+					_ = k8sClient.Delete(ctx, r)
+				}
+			})
+
+			reconciler := &BootDependencyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &corev1alpha1.BootDependency{}
+			Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+			return updated
+		}
+
+		It("should count a gRPC dependency as resolved when the service is SERVING", func() {
+			hs, host, port := startHealthServer()
+			hs.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+
+			updated := reconcileDeps("grpc-serving-resource", []corev1alpha1.ServiceDependency{
+				{Host: host, Port: port, GRPC: &corev1alpha1.GRPCProbe{Service: "payments"}},
+			})
+			Expect(updated.Status.ResolvedDependencies).To(Equal("1/1"))
+		})
+
+		It("should not count a gRPC dependency as resolved while the service is NOT_SERVING", func() {
+			// A raw TCP check would pass here because the port is open.
+			hs, host, port := startHealthServer()
+			hs.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+
+			updated := reconcileDeps("grpc-not-serving-resource", []corev1alpha1.ServiceDependency{
+				{Host: host, Port: port, GRPC: &corev1alpha1.GRPCProbe{Service: "payments"}},
+			})
+			Expect(updated.Status.ResolvedDependencies).To(Equal("0/1"))
+		})
+	})
+
+	Context("Job dependency", func() {
+		ctx := context.Background()
+
+		// createFailedJob creates a Job and marks it failed the way the Job controller does.
+		createFailedJob := func(name string) {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							RestartPolicy: corev1.RestartPolicyNever,
+							Containers:    []corev1.Container{{Name: "migrate", Image: "migrate:latest"}},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, job)).To(Succeed())
+			DeferCleanup(func() {
+				_ = k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))
+			})
+
+			now := metav1.Now()
+			job.Status = batchv1.JobStatus{
+				StartTime: &now,
+				Failed:    1,
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailureTarget, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded",
+						Message: "Job has reached the specified backoff limit", LastTransitionTime: now},
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded",
+						Message: "Job has reached the specified backoff limit", LastTransitionTime: now},
+				},
+			}
+			Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+		}
+
+		It("should put the BootDependency into a terminal failed state when the Job has failed", func() {
+			createFailedJob("migrate-failed")
+
+			nn := types.NamespacedName{Name: "job-failed-resource", Namespace: "default"}
+			resource := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{JobRef: &corev1alpha1.JobRef{Name: "migrate-failed"}},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() { _ = k8sClient.Delete(ctx, resource) })
+
+			reconciler := &BootDependencyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(BeZero())
+
+			updated := &corev1alpha1.BootDependency{}
+			Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+			Expect(updated.Status.Dependencies).To(HaveLen(1))
+			Expect(updated.Status.Dependencies[0].Name).To(Equal("job/migrate-failed"))
+			Expect(updated.Status.Dependencies[0].Reason).To(Equal(probe.ReasonJobFailed))
+			cond := meta.FindStatusCondition(updated.Status.Conditions, conditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("DependencyFailed"))
+			Expect(cond.Message).To(ContainSubstring("BackoffLimitExceeded"))
+		})
+
+		It("should keep polling the other dependencies while the Job has failed", func() {
+			createFailedJob("migrate-failed-polling")
+			lis, err := net.Listen("tcp", "127.0.0.1:0")
+			Expect(err).NotTo(HaveOccurred())
+			port := lis.Addr().(*net.TCPAddr).Port
+			Expect(lis.Close()).To(Succeed())
+
+			nn := types.NamespacedName{Name: "job-failed-polling-resource", Namespace: "default"}
+			resource := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{JobRef: &corev1alpha1.JobRef{Name: "migrate-failed-polling"}},
+						{Host: "127.0.0.1", Port: int32(port)},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+			DeferCleanup(func() { _ = k8sClient.Delete(ctx, resource) })
+
+			reconciler := &BootDependencyReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+			result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).To(Equal(requeueAfterNotReady))
+
+			updated := &corev1alpha1.BootDependency{}
+			Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+			Expect(updated.Status.Dependencies).To(HaveLen(2))
+			Expect(updated.Status.Dependencies[0].Reason).To(Equal(probe.ReasonJobFailed))
+			Expect(updated.Status.Dependencies[1].Ready).To(BeFalse())
+			cond := meta.FindStatusCondition(updated.Status.Conditions, conditionReady)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("DependencyFailed"))
+		})
 	})
 })
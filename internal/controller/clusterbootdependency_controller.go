@@ -0,0 +1,370 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
+)
+
+const (
+	// maxConcurrentProbes bounds the probes a ClusterBootDependency runs at once.
+	maxConcurrentProbes = 16
+	// maxFailingNamespaces bounds the namespaces listed in status.failingNamespaces.
+	maxFailingNamespaces = 20
+)
+
+// ClusterBootDependencyReconciler reconciles a ClusterBootDependency object
+type ClusterBootDependencyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	// Proxy is the operator-wide proxy for probes of host dependencies.
+	Proxy probe.Proxy
+	// ClusterDomain is the DNS domain of the cluster used to build Service hostnames.
+	// Defaults to cluster.local.
+	ClusterDomain string
+
+	// resources watches the kinds referenced by resourceRef dependencies. It is set up
+	// by SetupWithManager.
+	resources resourceWatcher
+}
+
+// +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=clusterbootdependencies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=clusterbootdependencies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=core.bootchain-operator.ruicoelho.dev,resources=clusterbootdependencies/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+// Reconcile probes the dependencies of a ClusterBootDependency from every namespace its
+// namespaceSelector selects and reports how many namespaces each is reachable from,
+// along with the namespaces that are not ready.
+func (r *ClusterBootDependencyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	start := time.Now()
+
+	var cbd corev1alpha1.ClusterBootDependency
+	if err := r.Get(ctx, req.NamespacedName, &cbd); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	namespaces, err := selectedNamespaces(ctx, r.Client, cbd.Spec.NamespaceSelector)
+	if err != nil {
+		log.Error(err, "Failed to list the selected namespaces")
+		reconcileTotal.WithLabelValues("error").Inc()
+		reconcileDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
+		return ctrl.Result{}, err
+	}
+
+	deps := cbd.Spec.DependsOn
+	for _, dep := range deps {
+		if dep.ResourceRef != nil {
+			if err := r.resources.watch(dep.ResourceRef); err != nil {
+				log.Error(err, "Failed to watch resource kind", "dependency", depLabel(dep))
+			}
+		}
+	}
+	results := r.probeAll(ctx, namespaces, deps)
+
+	total := len(deps)
+	readyNamespaces := 0
+	depReady := make([]int, total)
+	failing := make([]corev1alpha1.NamespaceDependencyStatus, 0, min(len(namespaces), maxFailingNamespaces))
+	// failed holds the first terminal failure, such as a failed Job. Probing again
+	// cannot resolve it, so polling stops unless another dependency is still pending.
+	var failed *corev1alpha1.DependencyStatus
+	var failedNamespace string
+	pending := false
+	for i, namespace := range namespaces {
+		var notReady []corev1alpha1.DependencyStatus
+		for j, res := range results[i] {
+			if res.err == nil {
+				depReady[j]++
+				continue
+			}
+			notReady = append(notReady, res.status)
+			switch {
+			case !probe.IsTerminal(res.err):
+				pending = true
+			case failed == nil:
+				failed = &results[i][j].status
+				failedNamespace = namespace
+			}
+		}
+		if len(notReady) == 0 {
+			readyNamespaces++
+			continue
+		}
+		resolved := fmt.Sprintf("%d/%d", total-len(notReady), total)
+		log.Info("Dependencies not reachable", "namespace", namespace, "resolved", resolved)
+		if len(failing) < maxFailingNamespaces {
+			failing = append(failing, corev1alpha1.NamespaceDependencyStatus{
+				Namespace:            namespace,
+				ResolvedDependencies: resolved,
+				Dependencies:         notReady,
+			})
+		}
+	}
+
+	depStatuses := make([]corev1alpha1.ClusterDependencyStatus, 0, total)
+	for j, dep := range deps {
+		depStatuses = append(depStatuses, corev1alpha1.ClusterDependencyStatus{
+			Name:            depName(dep),
+			Ready:           depReady[j] == len(namespaces),
+			ReadyNamespaces: fmt.Sprintf("%d/%d", depReady[j], len(namespaces)),
+		})
+	}
+
+	patch := client.MergeFrom(cbd.DeepCopy())
+	cbd.Status.ReadyNamespaces = fmt.Sprintf("%d/%d", readyNamespaces, len(namespaces))
+	cbd.Status.Dependencies = depStatuses
+	cbd.Status.FailingNamespaces = failing
+
+	allReady := readyNamespaces == len(namespaces)
+	cond := metav1.Condition{
+		Type:               conditionReady,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: cbd.Generation,
+	}
+	switch {
+	case allReady:
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "AllNamespacesReady"
+		cond.Message = fmt.Sprintf("All %d dependencies are reachable from all %d selected namespaces", total, len(namespaces))
+	case failed != nil:
+		cond.Reason = "DependencyFailed"
+		cond.Message = fmt.Sprintf("Dependency %s failed in namespace %s: %s", failed.Name, failedNamespace, failed.Message)
+		r.Recorder.Eventf(&cbd, corev1.EventTypeWarning, "DependencyFailed",
+			"Dependency %s failed in namespace %s: %s", failed.Name, failedNamespace, failed.Message)
+	default:
+		cond.Reason = "NamespacesNotReady"
+		cond.Message = fmt.Sprintf("%d/%d selected namespaces have all dependencies reachable", readyNamespaces, len(namespaces))
+		r.Recorder.Event(&cbd, corev1.EventTypeWarning, "DependenciesNotReady", cond.Message)
+	}
+	meta.SetStatusCondition(&cbd.Status.Conditions, cond)
+
+	if err := r.Status().Patch(ctx, &cbd, patch); err != nil {
+		log.Error(err, "Failed to patch status")
+		reconcileTotal.WithLabelValues("error").Inc()
+		reconcileDuration.WithLabelValues("error").Observe(time.Since(start).Seconds())
+		return ctrl.Result{}, err
+	}
+
+	reconcileTotal.WithLabelValues("success").Inc()
+	reconcileDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
+
+	switch {
+	case allReady:
+		return ctrl.Result{RequeueAfter: requeueAfterReady}, nil
+	case !pending:
+		// Terminal: the Job watch requeues once the failed Job is replaced.
+		return ctrl.Result{}, nil
+	default:
+		return ctrl.Result{RequeueAfter: requeueAfterNotReady}, nil
+	}
+}
+
+// probeResult is the outcome of probing one dependency from one namespace.
+type probeResult struct {
+	status corev1alpha1.DependencyStatus
+	err    error
+}
+
+// probeAll probes every dependency from every namespace, running at most
+// maxConcurrentProbes probes at once, and returns the result of deps[j] in
+// namespaces[i] as results[i][j]. A dependency that does not depend on the namespace
+// is probed once and its result shared by every namespace.
+func (r *ClusterBootDependencyReconciler) probeAll(ctx context.Context, namespaces []string, deps []corev1alpha1.ServiceDependency) [][]probeResult {
+	results := make([][]probeResult, len(namespaces))
+	for i := range results {
+		results[i] = make([]probeResult, len(deps))
+	}
+	if len(namespaces) == 0 {
+		return results
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxConcurrentProbes)
+	run := func(namespace string, dep corev1alpha1.ServiceDependency, store func(probeResult)) {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			status, err := probeDependency(ctx, r.Client, r.Proxy, r.ClusterDomain, namespace, dep)
+			store(probeResult{status: status, err: err})
+		})
+	}
+	for j, dep := range deps {
+		if r.namespaceIndependent(dep) {
+			run(namespaces[0], dep, func(res probeResult) {
+				for i := range results {
+					results[i][j] = res
+				}
+			})
+			continue
+		}
+		for i, namespace := range namespaces {
+			run(namespace, dep, func(res probeResult) { results[i][j] = res })
+		}
+	}
+	wg.Wait()
+	return results
+}
+
+// namespaceIndependent reports whether probing dep gives the same result from every
+// namespace: it reaches a host, a Service in a namespace of its own, or a resource
+// with a namespace of its own or of a cluster-scoped kind, and reads no Secret or
+// ConfigMap, which would come from each namespace.
+func (r *ClusterBootDependencyReconciler) namespaceIndependent(dep corev1alpha1.ServiceDependency) bool {
+	if len(probe.SecretRefs(dep)) > 0 || len(probe.ConfigMapRefs(dep)) > 0 {
+		return false
+	}
+	switch {
+	case dep.Host != "":
+		return true
+	case dep.Service != "":
+		return dep.Namespace != ""
+	case dep.ResourceRef != nil:
+		if dep.ResourceRef.Namespace != "" {
+			return true
+		}
+		gvk := schema.FromAPIVersionAndKind(dep.ResourceRef.APIVersion, dep.ResourceRef.Kind)
+		mapping, err := r.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+		return err == nil && mapping.Scope.Name() == meta.RESTScopeNameRoot
+	default:
+		return false
+	}
+}
+
+// selectedNamespaces returns the names of the namespaces that selector selects, all of
+// them when it is nil, sorted by name.
+func selectedNamespaces(ctx context.Context, c client.Reader, selector *metav1.LabelSelector) ([]string, error) {
+	sel := labels.Everything()
+	if selector != nil {
+		var err error
+		if sel, err = metav1.LabelSelectorAsSelector(selector); err != nil {
+			return nil, err
+		}
+	}
+	var list corev1.NamespaceList
+	if err := c.List(ctx, &list, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		if ns.Status.Phase == corev1.NamespaceTerminating {
+			continue
+		}
+		names = append(names, ns.Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. Besides Namespaces, it
+// watches Jobs, EndpointSlices and resourceRef kinds like the BootDependency controller.
+func (r *ClusterBootDependencyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := ctrl.NewControllerManagedBy(mgr).
+		For(&corev1alpha1.ClusterBootDependency{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.requestsForNamespace)).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.requestsForJob)).
+		Watches(&discoveryv1.EndpointSlice{}, handler.EnqueueRequestsFromMapFunc(r.requestsForEndpointSlice)).
+		Named("clusterbootdependency").
+		Build(r)
+	if err != nil {
+		return err
+	}
+	r.resources = resourceWatcher{
+		controller: c,
+		cache:      mgr.GetCache(),
+		handler:    handler.EnqueueRequestsFromMapFunc(r.requestsForResource),
+	}
+	return nil
+}
+
+// requestsForNamespace maps a Namespace event to every ClusterBootDependency, so that a
+// namespace that is created, relabelled or deleted is picked up without waiting for the
+// next poll.
+func (r *ClusterBootDependencyReconciler) requestsForNamespace(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsFor(ctx, "Namespace", obj, func([]corev1alpha1.ServiceDependency) bool { return true })
+}
+
+// requestsForJob maps a Job event to the ClusterBootDependencies that reference it by
+// name or selector.
+func (r *ClusterBootDependencyReconciler) requestsForJob(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsFor(ctx, "Job", obj, func(deps []corev1alpha1.ServiceDependency) bool {
+		return referencesJob(deps, obj)
+	})
+}
+
+// requestsForEndpointSlice maps an EndpointSlice event to the ClusterBootDependencies
+// that depend on the slice's Service.
+func (r *ClusterBootDependencyReconciler) requestsForEndpointSlice(ctx context.Context, obj client.Object) []reconcile.Request {
+	if obj.GetLabels()[discoveryv1.LabelServiceName] == "" {
+		return nil
+	}
+	return r.requestsFor(ctx, "EndpointSlice", obj, func(deps []corev1alpha1.ServiceDependency) bool {
+		return referencesEndpointSlice(deps, "", obj)
+	})
+}
+
+// requestsForResource maps an event on a watched object to the ClusterBootDependencies
+// whose resourceRef points at it.
+func (r *ClusterBootDependencyReconciler) requestsForResource(ctx context.Context, obj client.Object) []reconcile.Request {
+	return r.requestsFor(ctx, "resource", obj, func(deps []corev1alpha1.ServiceDependency) bool {
+		return referencesResource(deps, "", obj)
+	})
+}
+
+// requestsFor returns a request for every ClusterBootDependency whose dependencies
+// match. Namespaces are not checked against namespaceSelector: an extra reconcile is
+// cheaper than reading the object's namespace.
+func (r *ClusterBootDependencyReconciler) requestsFor(ctx context.Context, kind string, obj client.Object,
+	match func([]corev1alpha1.ServiceDependency) bool) []reconcile.Request {
+	var list corev1alpha1.ClusterBootDependencyList
+	if err := r.List(ctx, &list); err != nil {
+		logf.FromContext(ctx).Error(err, "Failed to list ClusterBootDependencies for "+kind, "name", obj.GetName())
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cbd := range list.Items {
+		if match(cbd.Spec.DependsOn) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: cbd.Name}})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("ClusterBootDependency Controller", func() {
+	ctx := context.Background()
+
+	// createNamespace creates a namespace with labels. envtest has no namespace
+	// controller, so namespaces are never removed and each spec uses its own names.
+	createNamespace := func(name string, labels map[string]string) {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+	}
+
+	It("should report the readiness of the dependencies in every selected namespace", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() { _ = lis.Close() })
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.Close()
+			}
+		}()
+		port := int32(lis.Addr().(*net.TCPAddr).Port)
+
+		meshLabels := map[string]string{"bootchain-test/mesh": "enabled"}
+		createNamespace("mesh-orders", meshLabels)
+		createNamespace("mesh-payments", meshLabels)
+		createNamespace("no-mesh", nil)
+
+		nn := types.NamespacedName{Name: "platform-baseline"}
+		resource := &corev1alpha1.ClusterBootDependency{
+			ObjectMeta: metav1.ObjectMeta{Name: nn.Name},
+			Spec: corev1alpha1.ClusterBootDependencySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: meshLabels},
+				DependsOn:         []corev1alpha1.ServiceDependency{{Host: "127.0.0.1", Port: port}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, resource)
+
+		reconciler := &ClusterBootDependencyReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &corev1alpha1.ClusterBootDependency{}
+		Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+		Expect(updated.Status.ReadyNamespaces).To(Equal("2/2"))
+		Expect(updated.Status.Dependencies).To(ConsistOf(corev1alpha1.ClusterDependencyStatus{
+			Name: net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port))), Ready: true, ReadyNamespaces: "2/2",
+		}))
+		Expect(updated.Status.FailingNamespaces).To(BeEmpty())
+		Expect(meta.IsStatusConditionTrue(updated.Status.Conditions, conditionReady)).To(BeTrue())
+
+		By("closing the listener so the dependency becomes unreachable")
+		Expect(lis.Close()).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: nn})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, nn, updated)).To(Succeed())
+		Expect(updated.Status.ReadyNamespaces).To(Equal("0/2"))
+		Expect(updated.Status.Dependencies[0].ReadyNamespaces).To(Equal("0/2"))
+		Expect(updated.Status.FailingNamespaces).To(HaveLen(2))
+		Expect(updated.Status.FailingNamespaces[0].Namespace).To(Equal("mesh-orders"))
+		Expect(updated.Status.FailingNamespaces[0].ResolvedDependencies).To(Equal("0/1"))
+		Expect(updated.Status.FailingNamespaces[0].Dependencies[0].Ready).To(BeFalse())
+		Expect(updated.Status.FailingNamespaces[1].Namespace).To(Equal("mesh-payments"))
+		cond := meta.FindStatusCondition(updated.Status.Conditions, conditionReady)
+		Expect(cond).NotTo(BeNil())
+		Expect(cond.Reason).To(Equal("NamespacesNotReady"))
+	})
+})
@@ -0,0 +1,363 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// ReasonVirtualHostNotAllowed is reported when the AMQP broker refuses to open the
+// virtual host, e.g. because it does not exist or the user has no access to it.
+const ReasonVirtualHostNotAllowed = "VirtualHostNotAllowed"
+
+const (
+	amqpFrameMethod = 1
+	amqpFrameEnd    = 0xce
+	amqpMaxFrame    = 1 << 20
+
+	amqpClassConnection = 10
+
+	amqpMethodStart   = 10
+	amqpMethodStartOk = 11
+	amqpMethodTune    = 30
+	amqpMethodTuneOk  = 31
+	amqpMethodOpen    = 40
+	amqpMethodOpenOk  = 41
+	amqpMethodClose   = 50
+	amqpMethodCloseOk = 51
+
+	amqpReplySuccess       = 200
+	amqpReplyAccessRefused = 403
+	amqpReplyNotAllowed    = 530
+
+	amqpDefaultVHost = "/"
+)
+
+var amqpProtocolHeader = []byte("AMQP\x00\x00\x09\x01")
+
+// AMQP performs the AMQP 0-9-1 protocol header exchange with the broker on addr and
+// returns the product and version it announces in Connection.Start. Without
+// credentials the broker is ready once it sends Connection.Start. With credentials
+// the probe also authenticates with PLAIN, completes Connection.Tune and opens
+// spec.VHost.
+func AMQP(ctx context.Context, addr string, spec corev1alpha1.AMQPProbe, secrets SecretReader) (string, error) {
+	var creds *authCredentials
+	if spec.CredentialsSecretRef != nil {
+		c, err := readCredentials(ctx, secrets, spec.CredentialsSecretRef)
+		if err != nil {
+			return "", err
+		}
+		creds = &c
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	ac := &amqpConn{conn: conn, r: bufio.NewReader(conn)}
+	if _, err := conn.Write(amqpProtocolHeader); err != nil {
+		return "", err
+	}
+	start, err := ac.readStart()
+	if err != nil {
+		return "", err
+	}
+	detail := strings.TrimSpace(start.product + " " + start.version)
+	if creds == nil {
+		return detail, nil
+	}
+
+	if !strings.Contains(" "+start.mechanisms+" ", " PLAIN ") {
+		return "", errorf(ReasonAuthenticationFailed, "broker does not offer the PLAIN mechanism (offers %q)", start.mechanisms)
+	}
+	if err := ac.sendStartOk(*creds); err != nil {
+		return "", err
+	}
+
+	tune, err := ac.readMethod(amqpMethodTune)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			// Brokers without the authentication_failure_close capability close
+			// the socket instead of sending Connection.Close.
+			return "", errorf(ReasonAuthenticationFailed, "broker closed the connection during authentication")
+		}
+		return "", err
+	}
+	if err := ac.sendTuneOk(tune); err != nil {
+		return "", err
+	}
+
+	vhost := spec.VHost
+	if vhost == "" {
+		vhost = amqpDefaultVHost
+	}
+	var open []byte
+	open = appendShortStr(open, vhost)
+	open = appendShortStr(open, "") // reserved-1
+	open = append(open, 0)          // reserved-2
+	if err := ac.sendMethod(amqpMethodOpen, open); err != nil {
+		return "", err
+	}
+	if _, err := ac.readMethod(amqpMethodOpenOk); err != nil {
+		return "", err
+	}
+
+	closeArgs := binary.BigEndian.AppendUint16(nil, amqpReplySuccess)
+	closeArgs = appendShortStr(closeArgs, "")
+	closeArgs = binary.BigEndian.AppendUint32(closeArgs, 0) // class-id, method-id
+	_ = ac.sendMethod(amqpMethodClose, closeArgs)
+	return detail, nil
+}
+
+// amqpConn is a minimal AMQP 0-9-1 client connection on channel 0.
+type amqpConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// amqpStart holds the parts of Connection.Start the probe uses.
+type amqpStart struct {
+	product    string
+	version    string
+	mechanisms string
+}
+
+// readStart reads Connection.Start. A broker that does not speak AMQP 0-9-1 answers
+// with its own protocol header instead.
+func (c *amqpConn) readStart() (amqpStart, error) {
+	var start amqpStart
+	peek, err := c.r.Peek(4)
+	if err != nil {
+		return start, err
+	}
+	if string(peek) == "AMQP" {
+		hdr := make([]byte, 8)
+		_, _ = io.ReadFull(c.r, hdr)
+		return start, errorf(ReasonProtocolError, "broker does not support AMQP 0-9-1 (offers %q)", hdr)
+	}
+
+	args, err := c.readMethod(amqpMethodStart)
+	if err != nil {
+		return start, err
+	}
+	r := &amqpReader{b: args}
+	r.next(2) // version-major, version-minor
+	props := r.table()
+	start.product = props["product"]
+	start.version = props["version"]
+	start.mechanisms = r.longStr()
+	return start, r.err
+}
+
+// sendStartOk answers Connection.Start with PLAIN credentials.
+func (c *amqpConn) sendStartOk(creds authCredentials) error {
+	var args []byte
+	props := appendShortStr(nil, "product")
+	props = append(props, 'S')
+	props = appendLongStr(props, "bootchain-probe")
+	args = appendLongStr(args, string(props))
+	args = appendShortStr(args, "PLAIN")
+	args = appendLongStr(args, "\x00"+creds.username+"\x00"+creds.password)
+	args = appendShortStr(args, "en_US")
+	return c.sendMethod(amqpMethodStartOk, args)
+}
+
+// sendTuneOk accepts the broker's channel-max and frame-max and disables heartbeats.
+func (c *amqpConn) sendTuneOk(tune []byte) error {
+	if len(tune) < 8 {
+		return errorf(ReasonProtocolError, "short Connection.Tune")
+	}
+	args := append([]byte{}, tune[0:6]...) // channel-max, frame-max
+	args = binary.BigEndian.AppendUint16(args, 0)
+	return c.sendMethod(amqpMethodTuneOk, args)
+}
+
+// sendMethod writes a Connection method frame on channel 0.
+func (c *amqpConn) sendMethod(method uint16, args []byte) error {
+	payload := binary.BigEndian.AppendUint16(nil, amqpClassConnection)
+	payload = binary.BigEndian.AppendUint16(payload, method)
+	payload = append(payload, args...)
+
+	frame := []byte{amqpFrameMethod, 0, 0}
+	frame = binary.BigEndian.AppendUint32(frame, uint32(len(payload)))
+	frame = append(frame, payload...)
+	frame = append(frame, amqpFrameEnd)
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// readMethod reads the next method frame and returns its arguments when it is the
+// expected Connection method. Connection.Close from the broker is returned as an error.
+func (c *amqpConn) readMethod(want uint16) ([]byte, error) {
+	for {
+		hdr := make([]byte, 7)
+		if _, err := io.ReadFull(c.r, hdr); err != nil {
+			return nil, err
+		}
+		size := int(binary.BigEndian.Uint32(hdr[3:7]))
+		if size > amqpMaxFrame {
+			return nil, errorf(ReasonProtocolError, "frame of %d bytes exceeds limit", size)
+		}
+		payload := make([]byte, size+1)
+		if _, err := io.ReadFull(c.r, payload); err != nil {
+			return nil, err
+		}
+		if payload[size] != amqpFrameEnd {
+			return nil, errorf(ReasonProtocolError, "invalid frame end")
+		}
+		if hdr[0] != amqpFrameMethod {
+			continue // heartbeats and other frame types are irrelevant here
+		}
+		if size < 4 {
+			return nil, errorf(ReasonProtocolError, "short method frame")
+		}
+
+		class := binary.BigEndian.Uint16(payload[0:2])
+		method := binary.BigEndian.Uint16(payload[2:4])
+		args := payload[4:size]
+		switch {
+		case class == amqpClassConnection && method == want:
+			return args, nil
+		case class == amqpClassConnection && method == amqpMethodClose:
+			_ = c.sendMethod(amqpMethodCloseOk, nil)
+			return nil, amqpCloseError(args)
+		default:
+			return nil, errorf(ReasonProtocolError, "unexpected method %d.%d", class, method)
+		}
+	}
+}
+
+// amqpCloseError maps the reply code of Connection.Close to a probe reason.
+func amqpCloseError(args []byte) error {
+	r := &amqpReader{b: args}
+	code := binary.BigEndian.Uint16(r.next(2))
+	text := r.shortStr()
+	if r.err != nil {
+		return r.err
+	}
+	switch code {
+	case amqpReplyAccessRefused:
+		return errorf(ReasonAuthenticationFailed, "%d %s", code, text)
+	case amqpReplyNotAllowed:
+		return errorf(ReasonVirtualHostNotAllowed, "%d %s", code, text)
+	default:
+		return errorf(ReasonServerError, "%d %s", code, text)
+	}
+}
+
+func appendShortStr(b []byte, s string) []byte {
+	b = append(b, byte(len(s)))
+	return append(b, s...)
+}
+
+func appendLongStr(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// amqpReader decodes AMQP 0-9-1 method arguments and records the first error.
+type amqpReader struct {
+	b   []byte
+	err error
+}
+
+// amqpZero is returned by next once decoding failed, so that fixed-size fields still
+// decode as zero without allocating for lengths announced by the broker.
+var amqpZero [8]byte
+
+// next returns the next n bytes. Once the arguments are exhausted it records an
+// error and returns zero bytes for fixed-size fields and nil for longer ones.
+func (r *amqpReader) next(n int) []byte {
+	if r.err == nil && (n < 0 || len(r.b) < n) {
+		r.err = errorf(ReasonProtocolError, "truncated method arguments")
+	}
+	if r.err != nil {
+		if n < 0 || n > len(amqpZero) {
+			return nil
+		}
+		return amqpZero[:n]
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *amqpReader) shortStr() string {
+	return string(r.next(int(r.next(1)[0])))
+}
+
+func (r *amqpReader) longStr() string {
+	return string(r.next(int(binary.BigEndian.Uint32(r.next(4)))))
+}
+
+// table decodes a field table. Only string values are kept; other values are
+// skipped.
+func (r *amqpReader) table() map[string]string {
+	fields := map[string]string{}
+	t := &amqpReader{b: r.next(int(binary.BigEndian.Uint32(r.next(4))))}
+	for len(t.b) > 0 && t.err == nil {
+		name := t.shortStr()
+		if v, ok := t.value(); ok {
+			fields[name] = v
+		}
+	}
+	if t.err != nil && r.err == nil {
+		r.err = t.err
+	}
+	return fields
+}
+
+// value decodes a single field value and reports whether it is a string.
+func (r *amqpReader) value() (string, bool) {
+	switch typ := r.next(1)[0]; typ {
+	case 'S':
+		return r.longStr(), true
+	case 's':
+		// RabbitMQ encodes int16 as 's'; short strings are not valid field values.
+		r.next(2)
+	case 't', 'b', 'B':
+		r.next(1)
+	case 'u':
+		r.next(2)
+	case 'I', 'i', 'f':
+		r.next(4)
+	case 'l', 'd', 'T':
+		r.next(8)
+	case 'D':
+		r.next(5)
+	case 'x', 'A', 'F':
+		r.next(int(binary.BigEndian.Uint32(r.next(4))))
+	case 'V':
+	default:
+		r.err = errorf(ReasonProtocolError, "unknown field type %q", typ)
+	}
+	return "", false
+}
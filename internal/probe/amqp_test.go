@@ -0,0 +1,194 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// amqpBroker is the server side of a fake AMQP 0-9-1 connection.
+type amqpBroker struct {
+	*amqpConn
+}
+
+// expect reads the next method frame and returns its arguments, failing the spec
+// when it is not the wanted Connection method.
+func (b amqpBroker) expect(method uint16) []byte {
+	args, err := b.readMethod(method)
+	Expect(err).NotTo(HaveOccurred())
+	return args
+}
+
+func (b amqpBroker) start() {
+	var props []byte
+	for _, kv := range [][2]string{{"product", "RabbitMQ"}, {"version", "3.13.1"}} {
+		props = appendShortStr(props, kv[0])
+		props = append(props, 'S')
+		props = appendLongStr(props, kv[1])
+	}
+	// A nested capabilities table and a boolean exercise the value skipper.
+	caps := appendShortStr(nil, "authentication_failure_close")
+	caps = append(caps, 't', 1)
+	props = appendShortStr(props, "capabilities")
+	props = append(props, 'F')
+	props = appendLongStr(props, string(caps))
+
+	args := []byte{0, 9}
+	args = appendLongStr(args, string(props))
+	args = appendLongStr(args, "AMQPLAIN PLAIN")
+	args = appendLongStr(args, "en_US")
+	Expect(b.sendMethod(amqpMethodStart, args)).To(Succeed())
+}
+
+func (b amqpBroker) close(code uint16, text string) {
+	args := binary.BigEndian.AppendUint16(nil, code)
+	args = appendShortStr(args, text)
+	args = binary.BigEndian.AppendUint32(args, 0)
+	_ = b.sendMethod(amqpMethodClose, args)
+}
+
+// startFakeAMQP accepts one connection, checks the protocol header and hands the
+// connection to handle.
+func startFakeAMQP(handle func(b amqpBroker)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	go func() {
+		defer GinkgoRecover()
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		hdr := make([]byte, 8)
+		if _, err := io.ReadFull(conn, hdr); err != nil {
+			return
+		}
+		Expect(hdr).To(Equal(amqpProtocolHeader))
+		handle(amqpBroker{&amqpConn{conn: conn, r: bufio.NewReader(conn)}})
+	}()
+	return lis.Addr().String()
+}
+
+var _ = Describe("AMQP", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	credsRef := &corev1alpha1.SecretCredentialsRef{Name: "rabbit"}
+	secrets := mapSecrets{"rabbit/username": "app", "rabbit/password": "s3cret"}
+
+	It("should be ready once the broker sends Connection.Start", func() {
+		addr := startFakeAMQP(func(b amqpBroker) { b.start() })
+		detail, err := AMQP(probeCtx(), addr, corev1alpha1.AMQPProbe{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("RabbitMQ 3.13.1"))
+	})
+
+	It("should not be ready when the broker closes the connection during boot", func() {
+		addr := startFakeAMQP(func(amqpBroker) {})
+		_, err := AMQP(probeCtx(), addr, corev1alpha1.AMQPProbe{}, nil)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should fail when the broker answers with a different protocol header", func() {
+		addr := startFakeAMQP(func(b amqpBroker) {
+			_, _ = b.conn.Write([]byte("AMQP\x01\x01\x00\x0a"))
+		})
+		_, err := AMQP(probeCtx(), addr, corev1alpha1.AMQPProbe{}, nil)
+		Expect(Reason(err)).To(Equal(ReasonProtocolError))
+	})
+
+	It("should reject method arguments that announce more bytes than the frame holds", func() {
+		addr := startFakeAMQP(func(b amqpBroker) {
+			args := []byte{0, 9}
+			args = binary.BigEndian.AppendUint32(args, 0xfffffff0) // server-properties
+			_ = b.sendMethod(amqpMethodStart, args)
+		})
+		_, err := AMQP(probeCtx(), addr, corev1alpha1.AMQPProbe{}, nil)
+		Expect(Reason(err)).To(Equal(ReasonProtocolError))
+
+		r := &amqpReader{b: binary.BigEndian.AppendUint32(nil, 0xfffffff0)}
+		Expect(r.longStr()).To(BeEmpty())
+		Expect(Reason(r.err)).To(Equal(ReasonProtocolError))
+	})
+
+	It("should authenticate, tune and open the virtual host", func() {
+		addr := startFakeAMQP(func(b amqpBroker) {
+			b.start()
+			r := &amqpReader{b: b.expect(amqpMethodStartOk)}
+			r.table()
+			Expect(r.shortStr()).To(Equal("PLAIN"))
+			Expect(r.longStr()).To(Equal("\x00app\x00s3cret"))
+
+			tune := binary.BigEndian.AppendUint16(nil, 2047)
+			tune = binary.BigEndian.AppendUint32(tune, 131072)
+			tune = binary.BigEndian.AppendUint16(tune, 60)
+			Expect(b.sendMethod(amqpMethodTune, tune)).To(Succeed())
+			tuneOk := b.expect(amqpMethodTuneOk)
+			Expect(binary.BigEndian.Uint16(tuneOk[6:8])).To(BeZero(), "heartbeats should be disabled")
+
+			r = &amqpReader{b: b.expect(amqpMethodOpen)}
+			Expect(r.shortStr()).To(Equal("orders"))
+			Expect(b.sendMethod(amqpMethodOpenOk, appendShortStr(nil, ""))).To(Succeed())
+			b.expect(amqpMethodClose)
+		})
+
+		spec := corev1alpha1.AMQPProbe{VHost: "orders", CredentialsSecretRef: credsRef}
+		_, err := AMQP(probeCtx(), addr, spec, secrets)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report AuthenticationFailed on ACCESS_REFUSED", func() {
+		addr := startFakeAMQP(func(b amqpBroker) {
+			b.start()
+			b.expect(amqpMethodStartOk)
+			b.close(amqpReplyAccessRefused, "ACCESS_REFUSED - Login was refused using authentication mechanism PLAIN")
+		})
+		_, err := AMQP(probeCtx(), addr, corev1alpha1.AMQPProbe{CredentialsSecretRef: credsRef}, secrets)
+		Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+		Expect(err.Error()).To(ContainSubstring("ACCESS_REFUSED"))
+	})
+
+	It("should report VirtualHostNotAllowed when the vhost cannot be opened", func() {
+		addr := startFakeAMQP(func(b amqpBroker) {
+			b.start()
+			b.expect(amqpMethodStartOk)
+			tune := make([]byte, 8)
+			Expect(b.sendMethod(amqpMethodTune, tune)).To(Succeed())
+			b.expect(amqpMethodTuneOk)
+			b.expect(amqpMethodOpen)
+			b.close(amqpReplyNotAllowed, "NOT_ALLOWED - vhost missing not found")
+		})
+		spec := corev1alpha1.AMQPProbe{VHost: "missing", CredentialsSecretRef: credsRef}
+		_, err := AMQP(probeCtx(), addr, spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonVirtualHostNotAllowed))
+	})
+})
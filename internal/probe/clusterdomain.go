@@ -0,0 +1,86 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultClusterDomain is the cluster domain used when none is configured or detected.
+	DefaultClusterDomain = "cluster.local"
+	// ResolvConfPath is the resolver configuration the kubelet writes into every pod.
+	ResolvConfPath = "/etc/resolv.conf"
+)
+
+// DetectClusterDomain returns the cluster domain from the search path of the
+// resolv.conf at path. The kubelet lists <namespace>.svc.<domain>, svc.<domain> and
+// <domain> there for pods using the ClusterFirst DNS policy.
+func DetectClusterDomain(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "search" {
+			continue
+		}
+		for _, domain := range fields[1:] {
+			if rest, ok := strings.CutPrefix(strings.TrimSuffix(domain, "."), "svc."); ok && rest != "" {
+				return rest, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no svc.<domain> entry in the search path of %s", path)
+}
+
+// ServiceHost returns the fully qualified name of a Service:
+// <service>.<namespace>.svc.<clusterDomain>.
+func ServiceHost(service, namespace, clusterDomain string) string {
+	return fmt.Sprintf("%s.%s.svc.%s", service, namespace, clusterDomain)
+}
+
+// DependencyHost returns the host the controller connects to for dep: its host field,
+// or the FQDN of its Service, which lives in the dependency's namespace when set and in
+// namespace otherwise, so that the controller can resolve it from its own namespace.
+// An empty clusterDomain means cluster.local. A TLS probe verifies this name when it
+// has no serverName, and the webhook hands it to bootchain-probe so that the init
+// container, which connects by a shorter name, verifies the same one.
+func DependencyHost(dep corev1alpha1.ServiceDependency, namespace, clusterDomain string) string {
+	if dep.Host != "" {
+		return dep.Host
+	}
+	if clusterDomain == "" {
+		clusterDomain = DefaultClusterDomain
+	}
+	if dep.Namespace != "" {
+		namespace = dep.Namespace
+	}
+	return ServiceHost(dep.Service, namespace, clusterDomain)
+}
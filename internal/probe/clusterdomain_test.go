@@ -0,0 +1,52 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("DetectClusterDomain", func() {
+	resolvConf := func(content string) string {
+		path := filepath.Join(GinkgoT().TempDir(), "resolv.conf")
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		return path
+	}
+
+	It("should read the cluster domain from the pod's search path", func() {
+		path := resolvConf("nameserver 10.96.0.10\n" +
+			"search bootchain-system.svc.corp.internal svc.corp.internal corp.internal\n" +
+			"options ndots:5\n")
+		Expect(DetectClusterDomain(path)).To(Equal("corp.internal"))
+	})
+
+	It("should fail when the search path has no svc.<domain> entry", func() {
+		_, err := DetectClusterDomain(resolvConf("nameserver 8.8.8.8\nsearch example.com\n"))
+		Expect(err).To(HaveOccurred())
+
+		_, err = DetectClusterDomain(filepath.Join(GinkgoT().TempDir(), "missing"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should build the fully qualified name of a Service", func() {
+		Expect(ServiceHost("kafka", "platform", "corp.internal")).To(Equal("kafka.platform.svc.corp.internal"))
+	})
+})
@@ -0,0 +1,176 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the DNS probe.
+const (
+	// ReasonDNSRecordNotFound is reported when the name does not exist or has no
+	// records of the requested type.
+	ReasonDNSRecordNotFound = "DNSRecordNotFound"
+	// ReasonDNSResolutionFailed is reported when the resolver cannot be reached or
+	// answers with an error such as SERVFAIL.
+	ReasonDNSResolutionFailed = "DNSResolutionFailed"
+	// ReasonDNSAnswerMismatch is reported when the answer lacks an expected value.
+	ReasonDNSAnswerMismatch = "DNSAnswerMismatch"
+)
+
+const dnsDefaultPort = "53"
+
+// DNS looks up the record described by spec and succeeds once the answer contains
+// every value in spec.Expected, or is non-empty when nothing is expected. The record
+// name defaults to host. On success it returns the record type and the answer.
+func DNS(ctx context.Context, host string, spec corev1alpha1.DNSProbe) (string, error) {
+	name := spec.Name
+	if name == "" {
+		name = host
+	}
+	typ := spec.Type
+	if typ == "" {
+		typ = "A"
+	}
+
+	resolver := &net.Resolver{PreferGo: true}
+	if spec.Resolver != "" {
+		addr := resolverAddr(spec.Resolver)
+		resolver.Dial = func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		}
+	}
+
+	answer, err := lookup(ctx, resolver, typ, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return "", errorf(ReasonDNSRecordNotFound, "no %s record for %s", typ, name)
+		}
+		return "", errorf(ReasonDNSResolutionFailed, "%s lookup of %s failed: %v", typ, name, err)
+	}
+	if len(answer) == 0 {
+		return "", errorf(ReasonDNSRecordNotFound, "no %s record for %s", typ, name)
+	}
+
+	var missing []string
+	for _, want := range spec.Expected {
+		if !containsDNSValue(answer, typ, want) {
+			missing = append(missing, want)
+		}
+	}
+	if len(missing) > 0 {
+		return "", errorf(ReasonDNSAnswerMismatch, "%s %s resolved to %s, missing %s",
+			typ, name, strings.Join(answer, ", "), strings.Join(missing, ", "))
+	}
+	return typ + " " + strings.Join(answer, ", "), nil
+}
+
+// lookup resolves name and returns the answer as strings in the format used by
+// DNSProbe.Expected.
+func lookup(ctx context.Context, r *net.Resolver, typ, name string) ([]string, error) {
+	switch typ {
+	case "A", "AAAA":
+		network := "ip4"
+		if typ == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := r.LookupNetIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		answer := make([]string, len(ips))
+		for i, ip := range ips {
+			answer[i] = ip.Unmap().String()
+		}
+		return answer, nil
+	case "CNAME":
+		cname, err := r.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		// LookupCNAME returns the name itself when it has address records but no CNAME.
+		if sameDNSName(cname, name) {
+			return nil, nil
+		}
+		return []string{strings.TrimSuffix(cname, ".")}, nil
+	case "SRV":
+		_, srvs, err := r.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		answer := make([]string, len(srvs))
+		for i, srv := range srvs {
+			answer[i] = net.JoinHostPort(strings.TrimSuffix(srv.Target, "."), strconv.Itoa(int(srv.Port)))
+		}
+		return answer, nil
+	case "TXT":
+		return r.LookupTXT(ctx, name)
+	default:
+		return nil, fmt.Errorf("unsupported record type %q", typ)
+	}
+}
+
+// containsDNSValue reports whether answer contains want. Addresses are compared in
+// their canonical form and names case-insensitively without the trailing dot.
+func containsDNSValue(answer []string, typ, want string) bool {
+	for _, got := range answer {
+		switch typ {
+		case "A", "AAAA":
+			w, err := netip.ParseAddr(want)
+			if err == nil && w.Unmap().String() == got {
+				return true
+			}
+		case "CNAME":
+			if sameDNSName(got, want) {
+				return true
+			}
+		case "SRV":
+			gh, gp, _ := net.SplitHostPort(got)
+			wh, wp, err := net.SplitHostPort(want)
+			if err == nil && gp == wp && sameDNSName(gh, wh) {
+				return true
+			}
+		default:
+			if got == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func sameDNSName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// resolverAddr adds the default DNS port to a resolver given as a bare host or IP address.
+func resolverAddr(resolver string) string {
+	if _, _, err := net.SplitHostPort(resolver); err == nil {
+		return resolver
+	}
+	return net.JoinHostPort(strings.Trim(resolver, "[]"), dnsDefaultPort)
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/dns/dnsmessage"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// startFakeDNS serves UDP queries from zone, keyed by lower-case FQDN. Names that
+// are not in the zone get NXDOMAIN; a nil entry answers SERVFAIL.
+func startFakeDNS(zone map[string][]dnsmessage.ResourceBody) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(pc.Close)
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, from, err := pc.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			var p dnsmessage.Parser
+			hdr, err := p.Start(buf[:n])
+			if err != nil {
+				continue
+			}
+			q, err := p.Question()
+			if err != nil {
+				continue
+			}
+
+			resp := dnsmessage.Header{ID: hdr.ID, Response: true, Authoritative: true}
+			records, ok := zone[strings.ToLower(q.Name.String())]
+			switch {
+			case !ok:
+				resp.RCode = dnsmessage.RCodeNameError
+			case records == nil:
+				resp.RCode = dnsmessage.RCodeServerFailure
+			}
+			b := dnsmessage.NewBuilder(nil, resp)
+			_ = b.StartQuestions()
+			_ = b.Question(q)
+			_ = b.StartAnswers()
+			for _, rb := range records {
+				h := dnsmessage.ResourceHeader{Name: q.Name, Class: dnsmessage.ClassINET, TTL: 30}
+				switch r := rb.(type) {
+				case *dnsmessage.AResource:
+					if q.Type == dnsmessage.TypeA {
+						_ = b.AResource(h, *r)
+					}
+				case *dnsmessage.AAAAResource:
+					if q.Type == dnsmessage.TypeAAAA {
+						_ = b.AAAAResource(h, *r)
+					}
+				case *dnsmessage.CNAMEResource:
+					_ = b.CNAMEResource(h, *r)
+				case *dnsmessage.SRVResource:
+					if q.Type == dnsmessage.TypeSRV {
+						_ = b.SRVResource(h, *r)
+					}
+				case *dnsmessage.TXTResource:
+					if q.Type == dnsmessage.TypeTXT {
+						_ = b.TXTResource(h, *r)
+					}
+				}
+			}
+			msg, err := b.Finish()
+			if err != nil {
+				continue
+			}
+			_, _ = pc.WriteTo(msg, from)
+		}
+	}()
+	return pc.LocalAddr().String()
+}
+
+var _ = Describe("DNS", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	var resolver string
+	BeforeEach(func() {
+		resolver = startFakeDNS(map[string][]dnsmessage.ResourceBody{
+			"db.example.internal.": {
+				&dnsmessage.AResource{A: [4]byte{10, 0, 0, 5}},
+				&dnsmessage.AAAAResource{AAAA: [16]byte{0xfd, 0, 15: 5}},
+			},
+			"alias.example.internal.": {
+				&dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("db.example.internal.")},
+			},
+			"_postgres._tcp.example.internal.": {
+				&dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 5432, Target: dnsmessage.MustNewName("db.example.internal.")},
+			},
+			"version.example.internal.": {
+				&dnsmessage.TXTResource{TXT: []string{"v=2"}},
+			},
+			"broken.example.internal.": nil,
+		})
+	})
+
+	It("should resolve the host as an A record by default", func() {
+		detail, err := DNS(probeCtx(), "db.example.internal.", corev1alpha1.DNSProbe{Resolver: resolver})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("A 10.0.0.5"))
+	})
+
+	It("should match expected AAAA addresses in canonical form", func() {
+		spec := corev1alpha1.DNSProbe{Type: "AAAA", Expected: []string{"fd00:0:0::5"}, Resolver: resolver}
+		_, err := DNS(probeCtx(), "db.example.internal.", spec)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should look up the record name instead of the host when set", func() {
+		spec := corev1alpha1.DNSProbe{
+			Name: "_postgres._tcp.example.internal.", Type: "SRV",
+			Expected: []string{"DB.example.internal:5432"}, Resolver: resolver,
+		}
+		detail, err := DNS(probeCtx(), "ignored.example.internal.", spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("SRV db.example.internal:5432"))
+	})
+
+	It("should resolve CNAME and TXT records", func() {
+		spec := corev1alpha1.DNSProbe{Type: "CNAME", Expected: []string{"db.example.internal."}, Resolver: resolver}
+		_, err := DNS(probeCtx(), "alias.example.internal.", spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		spec = corev1alpha1.DNSProbe{Type: "TXT", Expected: []string{"v=2"}, Resolver: resolver}
+		_, err = DNS(probeCtx(), "version.example.internal.", spec)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report DNSAnswerMismatch when an expected value is missing", func() {
+		spec := corev1alpha1.DNSProbe{Expected: []string{"10.0.0.5", "10.0.0.6"}, Resolver: resolver}
+		_, err := DNS(probeCtx(), "db.example.internal.", spec)
+		Expect(Reason(err)).To(Equal(ReasonDNSAnswerMismatch))
+		Expect(err).To(MatchError("A db.example.internal. resolved to 10.0.0.5, missing 10.0.0.6"))
+	})
+
+	It("should report DNSRecordNotFound for a name that does not exist", func() {
+		_, err := DNS(probeCtx(), "missing.example.internal.", corev1alpha1.DNSProbe{Resolver: resolver})
+		Expect(Reason(err)).To(Equal(ReasonDNSRecordNotFound))
+	})
+
+	It("should report DNSRecordNotFound when the name has no CNAME", func() {
+		_, err := DNS(probeCtx(), "db.example.internal.", corev1alpha1.DNSProbe{Type: "CNAME", Resolver: resolver})
+		Expect(Reason(err)).To(Equal(ReasonDNSRecordNotFound))
+	})
+
+	It("should report DNSResolutionFailed when the server fails", func() {
+		_, err := DNS(probeCtx(), "broken.example.internal.", corev1alpha1.DNSProbe{Resolver: resolver})
+		Expect(Reason(err)).To(Equal(ReasonDNSResolutionFailed))
+	})
+
+	It("should add the default port to a bare resolver address", func() {
+		Expect(resolverAddr("10.96.0.10")).To(Equal("10.96.0.10:53"))
+		Expect(resolverAddr("[fd00::a]")).To(Equal("[fd00::a]:53"))
+		Expect(resolverAddr("fd00::a")).To(Equal("[fd00::a]:53"))
+		Expect(resolverAddr("dns.example.com:5353")).To(Equal("dns.example.com:5353"))
+	})
+})
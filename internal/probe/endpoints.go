@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// ReasonEndpointsNotReady is reported when a Service has fewer ready endpoints than required.
+const ReasonEndpointsNotReady = "EndpointsNotReady"
+
+// Endpoints lists the EndpointSlices of service in namespace through objects and
+// succeeds once the Service has at least cfg.MinReady ready endpoints and, with
+// cfg.PerZone, a ready endpoint in every zone it has endpoints in. An empty namespace
+// is the reader's own.
+func Endpoints(ctx context.Context, objects ObjectReader, namespace, service string, cfg corev1alpha1.EndpointsProbe) (string, error) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: service})
+	var list discoveryv1.EndpointSliceList
+	if err := objects.ListObjects(ctx, namespace, &list, selector); err != nil {
+		return "", objectError(err, "EndpointSlice", selector.String())
+	}
+	service = qualifiedName(namespace, service)
+
+	minReady := max(cfg.MinReady, 1)
+
+	// A dual-stack Service has one slice per address family listing the same pods, so
+	// endpoints are counted once per target (or per address when there is none).
+	ready := map[string]bool{}
+	zones := map[string]int{}
+	for _, slice := range list.Items {
+		for _, ep := range slice.Endpoints {
+			key := endpointKey(ep)
+			zone := ""
+			if ep.Zone != nil {
+				zone = *ep.Zone
+			}
+			if _, ok := zones[zone]; !ok {
+				zones[zone] = 0
+			}
+			// A nil ready condition means the endpoint is ready.
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			if ready[key] {
+				continue
+			}
+			ready[key] = true
+			zones[zone]++
+		}
+	}
+
+	if len(ready) < int(minReady) {
+		return "", errorf(ReasonEndpointsNotReady, "Service %s has %d of %d ready endpoints", service, len(ready), minReady)
+	}
+	if cfg.PerZone {
+		var empty []string
+		for zone, n := range zones {
+			if n == 0 {
+				empty = append(empty, zone)
+			}
+		}
+		if len(empty) > 0 {
+			slices.Sort(empty)
+			return "", errorf(ReasonEndpointsNotReady, "Service %s has no ready endpoints in zone %s", service, strings.Join(empty, ", "))
+		}
+		return fmt.Sprintf("%d ready endpoints in %d zones", len(ready), len(zones)), nil
+	}
+	return fmt.Sprintf("%d ready endpoints", len(ready)), nil
+}
+
+// endpointKey identifies the backend of an endpoint across EndpointSlices.
+func endpointKey(ep discoveryv1.Endpoint) string {
+	if ep.TargetRef != nil {
+		return ep.TargetRef.Kind + "/" + ep.TargetRef.Namespace + "/" + ep.TargetRef.Name
+	}
+	if len(ep.Addresses) > 0 {
+		return ep.Addresses[0]
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("Endpoints", func() {
+	// endpoint returns an endpoint backed by pod in zone.
+	endpoint := func(pod, addr, zone string, ready bool) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{
+			Addresses:  []string{addr},
+			Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+			TargetRef:  &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: pod},
+			Zone:       ptr.To(zone),
+		}
+	}
+	slice := func(name string, family discoveryv1.AddressType, eps ...discoveryv1.Endpoint) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "postgres"},
+			},
+			AddressType: family,
+			Endpoints:   eps,
+		}
+	}
+
+	It("should be ready once the Service has enough ready endpoints", func() {
+		objects := fakeObjectReader(slice("postgres-abc", discoveryv1.AddressTypeIPv4,
+			endpoint("postgres-0", "10.0.0.1", "a", true),
+			endpoint("postgres-1", "10.0.0.2", "b", true),
+			endpoint("postgres-2", "10.0.0.3", "b", false),
+		))
+		detail, err := Endpoints(context.Background(), objects, "", "postgres", corev1alpha1.EndpointsProbe{MinReady: 2})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("2 ready endpoints"))
+
+		_, err = Endpoints(context.Background(), objects, "", "postgres", corev1alpha1.EndpointsProbe{MinReady: 3})
+		Expect(Reason(err)).To(Equal(ReasonEndpointsNotReady))
+		Expect(err).To(MatchError("Service postgres has 2 of 3 ready endpoints"))
+	})
+
+	It("should count a pod listed in both address families once", func() {
+		objects := fakeObjectReader(
+			slice("postgres-v4", discoveryv1.AddressTypeIPv4, endpoint("postgres-0", "10.0.0.1", "a", true)),
+			slice("postgres-v6", discoveryv1.AddressTypeIPv6, endpoint("postgres-0", "fd00::1", "a", true)),
+		)
+		_, err := Endpoints(context.Background(), objects, "", "postgres", corev1alpha1.EndpointsProbe{MinReady: 2})
+		Expect(err).To(MatchError("Service postgres has 1 of 2 ready endpoints"))
+	})
+
+	It("should require a ready endpoint in every zone with perZone", func() {
+		objects := fakeObjectReader(slice("postgres-abc", discoveryv1.AddressTypeIPv4,
+			endpoint("postgres-0", "10.0.0.1", "a", true),
+			endpoint("postgres-1", "10.0.0.2", "b", false),
+		))
+		_, err := Endpoints(context.Background(), objects, "", "postgres", corev1alpha1.EndpointsProbe{MinReady: 1, PerZone: true})
+		Expect(Reason(err)).To(Equal(ReasonEndpointsNotReady))
+		Expect(err).To(MatchError("Service postgres has no ready endpoints in zone b"))
+	})
+
+	It("should report EndpointsNotReady for a Service without EndpointSlices", func() {
+		_, err := Endpoints(context.Background(), fakeObjectReader(), "", "postgres", corev1alpha1.EndpointsProbe{})
+		Expect(Reason(err)).To(Equal(ReasonEndpointsNotReady))
+		Expect(err).To(MatchError("Service postgres has 0 of 1 ready endpoints"))
+	})
+
+	It("should list the EndpointSlices of a Service in another namespace", func() {
+		s := slice("postgres-abc", discoveryv1.AddressTypeIPv4, endpoint("postgres-0", "10.0.0.1", "a", true))
+		s.Namespace = "platform"
+		objects := fakeObjectReader(s)
+		_, err := Endpoints(context.Background(), objects, "platform", "postgres", corev1alpha1.EndpointsProbe{})
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = Endpoints(context.Background(), objects, "", "postgres", corev1alpha1.EndpointsProbe{})
+		Expect(err).To(MatchError("Service postgres has 0 of 1 ready endpoints"))
+		_, err = Endpoints(context.Background(), objects, "platform", "postgres", corev1alpha1.EndpointsProbe{MinReady: 2})
+		Expect(err).To(MatchError("Service platform/postgres has 1 of 2 ready endpoints"))
+	})
+})
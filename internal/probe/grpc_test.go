@@ -0,0 +1,92 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("GRPC", func() {
+	// startHealthServer serves the standard gRPC health service on a random local port.
+	startHealthServer := func() (*health.Server, string) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		srv := grpc.NewServer()
+		hs := health.NewServer()
+		healthpb.RegisterHealthServer(srv, hs)
+		go func() { _ = srv.Serve(lis) }()
+		DeferCleanup(srv.Stop)
+		return hs, lis.Addr().String()
+	}
+
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	It("should succeed when the service reports SERVING", func() {
+		hs, addr := startHealthServer()
+		hs.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+
+		Expect(GRPC(probeCtx(), addr, corev1alpha1.GRPCProbe{Service: "payments"})).To(Succeed())
+	})
+
+	It("should fail when the service reports NOT_SERVING", func() {
+		hs, addr := startHealthServer()
+		hs.SetServingStatus("payments", healthpb.HealthCheckResponse_NOT_SERVING)
+
+		err := GRPC(probeCtx(), addr, corev1alpha1.GRPCProbe{Service: "payments"})
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("NOT_SERVING"))
+	})
+
+	It("should query the overall server health when no service is set", func() {
+		_, addr := startHealthServer()
+
+		Expect(GRPC(probeCtx(), addr, corev1alpha1.GRPCProbe{})).To(Succeed())
+	})
+
+	It("should fail when the service is unknown to the server", func() {
+		_, addr := startHealthServer()
+
+		Expect(GRPC(probeCtx(), addr, corev1alpha1.GRPCProbe{Service: "unknown"})).NotTo(Succeed())
+	})
+})
+
+var _ = Describe("MarshalDependency", func() {
+	It("should round-trip a dependency through UnmarshalDependency", func() {
+		dep := corev1alpha1.ServiceDependency{
+			Service: "api",
+			Port:    9090,
+			GRPC:    &corev1alpha1.GRPCProbe{Service: "api.v1", TLS: true},
+		}
+		decoded, err := UnmarshalDependency(MarshalDependency(dep))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(decoded).To(Equal(dep))
+	})
+})
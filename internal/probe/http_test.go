@@ -0,0 +1,199 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("HTTP", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	// serve starts an HTTP server that always answers with status and body.
+	serve := func(status int, body string) string {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.Header().Add("X-Instance", "a")
+			w.Header().Add("X-Instance", "b")
+			w.WriteHeader(status)
+			_, _ = w.Write([]byte(body))
+		}))
+		DeferCleanup(srv.Close)
+		return strings.TrimPrefix(srv.URL, "http://")
+	}
+
+	dep := func(expr string) corev1alpha1.ServiceDependency {
+		return corev1alpha1.ServiceDependency{HTTPPath: "/health", HTTPExpression: expr}
+	}
+
+	It("should be ready when the expression over the JSON body is true", func() {
+		addr := serve(200, `{"status":"UP","db":{"ready":true,"connections":12}}`)
+		err := HTTP(probeCtx(), "tcp", addr, dep(`body.status == "UP" && body.db.ready && body.db.connections > 10`), nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report ResponseAssertionFailed when the expression is false", func() {
+		addr := serve(200, `{"status":"DOWN"}`)
+		err := HTTP(probeCtx(), "tcp", addr, dep(`body.status == "UP"`), nil)
+		Expect(Reason(err)).To(Equal(ReasonResponseAssertionFailed))
+		Expect(err).To(MatchError(`httpExpression "body.status == \"UP\"" is false (HTTP 200)`))
+	})
+
+	It("should report ResponseAssertionFailed when the body lacks a referenced field", func() {
+		addr := serve(200, `{"status":"UP"}`)
+		err := HTTP(probeCtx(), "tcp", addr, dep(`body.db.ready == true`), nil)
+		Expect(Reason(err)).To(Equal(ReasonResponseAssertionFailed))
+	})
+
+	It("should stop evaluating an expression that exceeds the cost limit", func() {
+		items := make([]string, 200)
+		for i := range items {
+			items[i] = fmt.Sprintf("%d", i)
+		}
+		addr := serve(200, `{"items":[`+strings.Join(items, ",")+`]}`)
+		err := HTTP(probeCtx(), "tcp", addr, dep(`body.items.all(a, body.items.all(b, body.items.all(c, a >= 0)))`), nil)
+		Expect(Reason(err)).To(Equal(ReasonResponseAssertionFailed))
+		Expect(err).To(MatchError(ContainSubstring("cost limit exceeded")))
+	})
+
+	It("should expose status, lower-case headers and non-JSON bodies as a string", func() {
+		addr := serve(200, "OK")
+		expr := `status == 200 && headers["x-instance"] == "a, b" && body == "OK"`
+		Expect(HTTP(probeCtx(), "tcp", addr, dep(expr), nil)).To(Succeed())
+	})
+
+	It("should still require an accepted status code", func() {
+		addr := serve(503, `{"status":"UP"}`)
+		err := HTTP(probeCtx(), "tcp", addr, dep(`body.status == "UP"`), nil)
+		Expect(Reason(err)).To(Equal(ReasonUnexpectedStatus))
+	})
+
+	It("should send header values read from Secrets and ConfigMaps", func() {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer s3cret" || r.Header.Get("X-Tenant") != "acme" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}))
+		DeferCleanup(srv.Close)
+		addr := strings.TrimPrefix(srv.URL, "http://")
+
+		d := corev1alpha1.ServiceDependency{
+			HTTPPath: "/health",
+			HTTPHeaders: []corev1alpha1.HTTPHeader{
+				{Name: "Authorization", ValueFrom: &corev1alpha1.HTTPHeaderSource{
+					SecretKeyRef: &corev1alpha1.SecretKeySelector{Name: "api-token", Key: "header"},
+				}},
+				{Name: "X-Tenant", ValueFrom: &corev1alpha1.HTTPHeaderSource{
+					ConfigMapKeyRef: &corev1alpha1.ConfigMapKeySelector{Name: "api", Key: "tenant"},
+				}},
+			},
+		}
+		secrets := mapSecrets{"api-token/header": "Bearer s3cret", "configmap/api/tenant": "acme"}
+		Expect(HTTP(probeCtx(), "tcp", addr, d, secrets)).To(Succeed())
+
+		err := HTTP(probeCtx(), "tcp", addr, d, mapSecrets{"api-token/header": "Bearer s3cret"})
+		Expect(Reason(err)).To(Equal(ReasonConfigMapNotFound))
+	})
+
+	It("should verify the server against caBundleRef and present the client certificate", func() {
+		ca := newTestCA()
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(ca.cert)
+		srv.TLS = &tls.Config{
+			Certificates: []tls.Certificate{ca.issue(time.Hour)},
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			ClientCAs:    clientCAs,
+		}
+		srv.StartTLS()
+		DeferCleanup(srv.Close)
+		addr := strings.TrimPrefix(srv.URL, "https://")
+
+		client := ca.issue(time.Hour)
+		keyDER, err := x509.MarshalPKCS8PrivateKey(client.PrivateKey)
+		Expect(err).NotTo(HaveOccurred())
+		secrets := mapSecrets{
+			"client-tls/tls.crt":  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: client.Certificate[0]})),
+			"client-tls/tls.key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})),
+			"configmap/ca/ca.crt": ca.pem,
+		}
+
+		d := corev1alpha1.ServiceDependency{
+			HTTPPath:   "/health",
+			HTTPScheme: "https",
+			CABundleRef: &corev1alpha1.CABundleRef{
+				ConfigMapKeyRef: &corev1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+			ClientCertSecretRef: &corev1alpha1.ClientCertSecretRef{Name: "client-tls"},
+		}
+		Expect(HTTP(probeCtx(), "tcp", addr, d, secrets)).To(Succeed())
+		Expect(SecretRefs(d)).To(ConsistOf(
+			SecretKeyRef{Name: "client-tls", Key: "tls.crt"},
+			SecretKeyRef{Name: "client-tls", Key: "tls.key"},
+		))
+		Expect(ConfigMapRefs(d)).To(ConsistOf(ConfigMapKeyRef{Name: "ca", Key: "ca.crt"}))
+
+		By("failing the handshake without the client certificate")
+		d.ClientCertSecretRef = nil
+		Expect(HTTP(probeCtx(), "tcp", addr, d, secrets)).NotTo(Succeed())
+
+		By("failing verification against the system roots")
+		d.CABundleRef = nil
+		Expect(HTTP(probeCtx(), "tcp", addr, d, secrets)).To(MatchError(ContainSubstring("certificate")))
+	})
+
+	It("should report CertificateInvalid when caBundleRef holds no certificates", func() {
+		d := corev1alpha1.ServiceDependency{
+			HTTPPath:   "/health",
+			HTTPScheme: "https",
+			CABundleRef: &corev1alpha1.CABundleRef{
+				SecretKeyRef: &corev1alpha1.SecretKeySelector{Name: "ca", Key: "ca.crt"},
+			},
+		}
+		err := HTTP(probeCtx(), "tcp", "127.0.0.1:1", d, mapSecrets{"ca/ca.crt": "not a certificate"})
+		Expect(Reason(err)).To(Equal(ReasonCertificateInvalid))
+
+		err = HTTP(probeCtx(), "tcp", "127.0.0.1:1", d, mapSecrets{})
+		Expect(Reason(err)).To(Equal(ReasonSecretNotFound))
+	})
+
+	It("should reject expressions that do not compile or are not boolean", func() {
+		_, err := CompileHTTPExpression(`body.status ==`)
+		Expect(err).To(HaveOccurred())
+		_, err = CompileHTTPExpression(`status + 1`)
+		Expect(err).To(MatchError(ContainSubstring("must evaluate to bool")))
+		_, err = CompileHTTPExpression(`status == 200 && body.ready`)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+)
+
+// ReasonResponseAssertionFailed is reported when an HTTP response does not satisfy
+// the dependency's httpExpression.
+const ReasonResponseAssertionFailed = "ResponseAssertionFailed"
+
+// maxHTTPBodySize bounds how much of a response body is read for httpExpression.
+const maxHTTPBodySize = 1 << 20
+
+// httpCELEnv declares the variables available to httpExpression.
+var httpCELEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("status", cel.IntType),
+		cel.Variable("headers", cel.MapType(cel.StringType, cel.StringType)),
+		cel.Variable("body", cel.DynType),
+		cel.CrossTypeNumericComparisons(true),
+	)
+})
+
+// CompileHTTPExpression parses and type-checks an httpExpression, and bounds its
+// runtime cost as for a resourceRef expression. The validating webhook uses it to
+// reject invalid expressions before they reach the probes.
+func CompileHTTPExpression(expr string) (cel.Program, error) {
+	env, err := httpCELEnv()
+	if err != nil {
+		return nil, err
+	}
+	return compileBool(env, expr, cel.CostLimit(celCostLimit))
+}
+
+// celCostLimit bounds the runtime cost of an expression, so that an expression written
+// by a tenant cannot keep the controller busy, e.g. with nested comprehensions over a
+// large object.
+const celCostLimit = 1_000_000
+
+// compileBool compiles expr in env and checks that it evaluates to bool. A field of a
+// dynamically typed variable must be compared explicitly, e.g. `body.ready == true`.
+func compileBool(env *cel.Env, expr string, opts ...cel.ProgramOption) (cel.Program, error) {
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if !ast.OutputType().IsExactType(cel.BoolType) {
+		return nil, fmt.Errorf("expression must evaluate to bool, not %s", ast.OutputType())
+	}
+	return env.Program(ast, opts...)
+}
+
+// evalHTTPExpression evaluates expr against resp and returns nil when it is true.
+func evalHTTPExpression(expr string, resp *http.Response) error {
+	prg, err := CompileHTTPExpression(expr)
+	if err != nil {
+		return errorf(ReasonResponseAssertionFailed, "invalid httpExpression: %v", err)
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPBodySize))
+	if err != nil {
+		return err
+	}
+	var body any
+	if json.Unmarshal(raw, &body) != nil {
+		body = string(raw)
+	}
+	headers := make(map[string]string, len(resp.Header))
+	for name, values := range resp.Header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
+	}
+
+	out, _, err := prg.Eval(map[string]any{
+		"status":  resp.StatusCode,
+		"headers": headers,
+		"body":    body,
+	})
+	if err != nil {
+		return errorf(ReasonResponseAssertionFailed, "httpExpression: %v", err)
+	}
+	if ok, isBool := out.Value().(bool); !isBool || !ok {
+		return errorf(ReasonResponseAssertionFailed, "httpExpression %q is %v (HTTP %d)", expr, out.Value(), resp.StatusCode)
+	}
+	return nil
+}
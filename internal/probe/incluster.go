@@ -0,0 +1,84 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/labels"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// serviceAccountNamespaceFile holds the namespace of the pod's service account.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// InClusterObjects reads objects from the pod's own namespace, unless told otherwise,
+// with the pod's service account. The client is only created on first use, so dependencies that do not read
+// cluster state do not need API access.
+type InClusterObjects struct {
+	once      sync.Once
+	client    client.Client
+	namespace string
+	err       error
+}
+
+// GetObject implements ObjectReader.
+func (o *InClusterObjects) GetObject(ctx context.Context, name string, obj client.Object) error {
+	o.once.Do(o.init)
+	if o.err != nil {
+		return o.err
+	}
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = o.namespace
+	}
+	return o.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)
+}
+
+// ListObjects implements ObjectReader.
+func (o *InClusterObjects) ListObjects(ctx context.Context, namespace string, list client.ObjectList, selector labels.Selector) error {
+	o.once.Do(o.init)
+	if o.err != nil {
+		return o.err
+	}
+	if namespace == "" {
+		namespace = o.namespace
+	}
+	return o.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+}
+
+func (o *InClusterObjects) init() {
+	ns, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err != nil {
+		o.err = fmt.Errorf("failed to read the pod namespace (is the service account token mounted?): %w", err)
+		return
+	}
+	o.namespace = strings.TrimSpace(string(ns))
+
+	cfg, err := rest.InClusterConfig()
+	if err != nil {
+		o.err = err
+		return
+	}
+	o.client, o.err = client.New(cfg, client.Options{Scheme: clientgoscheme.Scheme})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the Job probe.
+const (
+	// ReasonJobNotComplete is reported while the Job is still running.
+	ReasonJobNotComplete = "JobNotComplete"
+	// ReasonJobFailed is reported, as a terminal failure, once the Job has failed.
+	ReasonJobFailed = "JobFailed"
+)
+
+// Job reads the referenced Job through objects and succeeds once it has the Complete
+// condition. A Job with the Failed condition is reported as a terminal failure that
+// carries the Job's own failure reason.
+func Job(ctx context.Context, objects ObjectReader, ref corev1alpha1.JobRef) (string, error) {
+	job, err := findJob(ctx, objects, ref)
+	if err != nil {
+		return "", err
+	}
+
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return fmt.Sprintf("Job %s complete", job.Name), nil
+		case batchv1.JobFailed:
+			return "", &Error{
+				Reason:   ReasonJobFailed,
+				Message:  fmt.Sprintf("Job %s failed (%s): %s", job.Name, c.Reason, c.Message),
+				Terminal: true,
+			}
+		}
+	}
+	return "", errorf(ReasonJobNotComplete, "Job %s has not completed (%d active, %d succeeded, %d failed)",
+		job.Name, job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+}
+
+// findJob returns the Job named by ref, or the most recently created Job matching
+// its selector.
+func findJob(ctx context.Context, objects ObjectReader, ref corev1alpha1.JobRef) (*batchv1.Job, error) {
+	if ref.Name != "" {
+		var job batchv1.Job
+		if err := objects.GetObject(ctx, ref.Name, &job); err != nil {
+			return nil, objectError(err, "Job", ref.Name)
+		}
+		return &job, nil
+	}
+
+	selector := labels.SelectorFromSet(ref.Selector)
+	var jobs batchv1.JobList
+	if err := objects.ListObjects(ctx, "", &jobs, selector); err != nil {
+		return nil, objectError(err, "Job", selector.String())
+	}
+	var latest *batchv1.Job
+	for i := range jobs.Items {
+		job := &jobs.Items[i]
+		if latest == nil || latest.CreationTimestamp.Before(&job.CreationTimestamp) ||
+			(latest.CreationTimestamp.Equal(&job.CreationTimestamp) && job.Name > latest.Name) {
+			latest = job
+		}
+	}
+	if latest == nil {
+		return nil, errorf(ReasonWorkloadNotFound, "no Job matches %s", selector)
+	}
+	return latest, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("Job", func() {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// job returns a Job created age after the reference time with the given true condition.
+	job := func(name string, age time.Duration, cond batchv1.JobConditionType) *batchv1.Job {
+		j := &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"app": "migrate"},
+				CreationTimestamp: metav1.NewTime(created.Add(age)),
+			},
+			Status: batchv1.JobStatus{Active: 1},
+		}
+		if cond != "" {
+			j.Status.Active = 0
+			j.Status.Conditions = []batchv1.JobCondition{{
+				Type:    cond,
+				Status:  corev1.ConditionTrue,
+				Reason:  "BackoffLimitExceeded",
+				Message: "Job has reached the specified backoff limit",
+			}}
+		}
+		return j
+	}
+
+	It("should be ready once the Job is complete", func() {
+		objects := fakeObjectReader(job("migrate-1", 0, batchv1.JobComplete))
+		detail, err := Job(context.Background(), objects, corev1alpha1.JobRef{Name: "migrate-1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("Job migrate-1 complete"))
+	})
+
+	It("should report JobNotComplete while the Job is running", func() {
+		objects := fakeObjectReader(job("migrate-1", 0, ""))
+		_, err := Job(context.Background(), objects, corev1alpha1.JobRef{Name: "migrate-1"})
+		Expect(Reason(err)).To(Equal(ReasonJobNotComplete))
+		Expect(IsTerminal(err)).To(BeFalse())
+	})
+
+	It("should report a terminal JobFailed with the Job's failure reason", func() {
+		objects := fakeObjectReader(job("migrate-1", 0, batchv1.JobFailed))
+		_, err := Job(context.Background(), objects, corev1alpha1.JobRef{Name: "migrate-1"})
+		Expect(Reason(err)).To(Equal(ReasonJobFailed))
+		Expect(IsTerminal(err)).To(BeTrue())
+		Expect(err).To(MatchError("Job migrate-1 failed (BackoffLimitExceeded): Job has reached the specified backoff limit"))
+	})
+
+	It("should use the most recently created Job matching the selector", func() {
+		objects := fakeObjectReader(
+			job("migrate-1", 0, batchv1.JobFailed),
+			job("migrate-2", time.Hour, batchv1.JobComplete),
+		)
+		detail, err := Job(context.Background(), objects, corev1alpha1.JobRef{Selector: map[string]string{"app": "migrate"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("Job migrate-2 complete"))
+	})
+
+	It("should report WorkloadNotFound when no Job matches", func() {
+		_, err := Job(context.Background(), fakeObjectReader(), corev1alpha1.JobRef{Selector: map[string]string{"app": "migrate"}})
+		Expect(Reason(err)).To(Equal(ReasonWorkloadNotFound))
+		Expect(err).To(MatchError("no Job matches app=migrate"))
+
+		_, err = Job(context.Background(), fakeObjectReader(), corev1alpha1.JobRef{Name: "migrate-1"})
+		Expect(Reason(err)).To(Equal(ReasonWorkloadNotFound))
+	})
+})
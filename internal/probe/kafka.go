@@ -0,0 +1,281 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the Kafka probe.
+const (
+	ReasonControllerNotAvailable = "ControllerNotAvailable"
+	ReasonTopicsNotReady         = "TopicsNotReady"
+)
+
+const (
+	kafkaAPIKeyMetadata    = 3
+	kafkaAPIKeyAPIVersions = 18
+	kafkaMetadataVersion   = 4
+	kafkaClientID          = "bootchain-probe"
+	kafkaMaxResponseSize   = 4 << 20
+
+	kafkaErrUnknownTopicOrPartition = 3
+)
+
+// Kafka sends ApiVersions and Metadata requests to the broker on addr. The broker is
+// ready once it reports an active controller, which proves the controller quorum has
+// formed. When spec.Topics is set, every listed topic must also exist and every one of
+// its partitions must have a leader; the error message names the topics and
+// partitions that do not.
+func Kafka(ctx context.Context, addr string, spec corev1alpha1.KafkaProbe) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	kc := &kafkaConn{conn: conn}
+	if err := kc.checkAPIVersions(); err != nil {
+		return "", err
+	}
+	md, err := kc.metadata(spec.Topics)
+	if err != nil {
+		return "", err
+	}
+
+	if md.controllerID < 0 {
+		return "", errorf(ReasonControllerNotAvailable, "no active controller (%d brokers known)", len(md.brokers))
+	}
+	if problems := md.topicProblems(spec.Topics); len(problems) > 0 {
+		return "", errorf(ReasonTopicsNotReady, "%s", strings.Join(problems, "; "))
+	}
+	return fmt.Sprintf("%d broker(s), controller %d", len(md.brokers), md.controllerID), nil
+}
+
+// kafkaConn is a minimal Kafka protocol client connection.
+type kafkaConn struct {
+	conn          net.Conn
+	correlationID int32
+}
+
+// roundTrip sends a request with header v1 and returns the response body without
+// the correlation id.
+func (c *kafkaConn) roundTrip(apiKey, apiVersion int16, body []byte) (*kafkaReader, error) {
+	c.correlationID++
+	var req []byte
+	req = binary.BigEndian.AppendUint32(req, 0) // size, filled in below
+	req = binary.BigEndian.AppendUint16(req, uint16(apiKey))
+	req = binary.BigEndian.AppendUint16(req, uint16(apiVersion))
+	req = binary.BigEndian.AppendUint32(req, uint32(c.correlationID))
+	req = appendKafkaString(req, kafkaClientID)
+	req = append(req, body...)
+	binary.BigEndian.PutUint32(req[0:4], uint32(len(req)-4))
+	if _, err := c.conn.Write(req); err != nil {
+		return nil, err
+	}
+
+	hdr := make([]byte, 8)
+	if _, err := io.ReadFull(c.conn, hdr); err != nil {
+		return nil, err
+	}
+	size := int(binary.BigEndian.Uint32(hdr[0:4])) - 4
+	if size < 0 || size > kafkaMaxResponseSize {
+		return nil, errorf(ReasonProtocolError, "invalid response size %d", size)
+	}
+	if got := int32(binary.BigEndian.Uint32(hdr[4:8])); got != c.correlationID {
+		return nil, errorf(ReasonProtocolError, "unexpected correlation id %d", got)
+	}
+	resp := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, resp); err != nil {
+		return nil, err
+	}
+	return &kafkaReader{b: resp}, nil
+}
+
+// checkAPIVersions sends ApiVersions v0 and verifies that the broker supports the
+// Metadata version the probe uses.
+func (c *kafkaConn) checkAPIVersions() error {
+	r, err := c.roundTrip(kafkaAPIKeyAPIVersions, 0, nil)
+	if err != nil {
+		return err
+	}
+	if code := r.int16(); code != 0 {
+		return errorf(ReasonServerError, "ApiVersions failed with error code %d", code)
+	}
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		key, lo, hi := r.int16(), r.int16(), r.int16()
+		if key == kafkaAPIKeyMetadata {
+			if kafkaMetadataVersion < lo || kafkaMetadataVersion > hi {
+				return errorf(ReasonProtocolError, "broker supports Metadata v%d-v%d, need v%d", lo, hi, kafkaMetadataVersion)
+			}
+			return nil
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+	return errorf(ReasonProtocolError, "broker does not support the Metadata API")
+}
+
+// kafkaMetadata holds the parts of a Metadata response the probe uses.
+type kafkaMetadata struct {
+	brokers      []int32
+	controllerID int32
+	topics       map[string]kafkaTopic
+}
+
+type kafkaTopic struct {
+	errorCode  int16
+	leaderless []int32
+}
+
+// metadata sends a Metadata v4 request for topics without allowing auto-creation.
+// An empty topics list requests no topic metadata at all.
+func (c *kafkaConn) metadata(topics []string) (kafkaMetadata, error) {
+	var body []byte
+	body = binary.BigEndian.AppendUint32(body, uint32(len(topics)))
+	for _, t := range topics {
+		body = appendKafkaString(body, t)
+	}
+	body = append(body, 0) // allow_auto_topic_creation = false
+
+	md := kafkaMetadata{topics: map[string]kafkaTopic{}}
+	r, err := c.roundTrip(kafkaAPIKeyMetadata, kafkaMetadataVersion, body)
+	if err != nil {
+		return md, err
+	}
+
+	r.int32() // throttle_time_ms
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		md.brokers = append(md.brokers, r.int32())
+		r.string() // host
+		r.int32()  // port
+		r.string() // rack
+	}
+	r.string() // cluster_id
+	md.controllerID = r.int32()
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		var t kafkaTopic
+		t.errorCode = r.int16()
+		name := r.string()
+		r.bool() // is_internal
+		for p := r.int32(); p > 0 && r.err == nil; p-- {
+			r.int16() // error_code
+			index := r.int32()
+			if leader := r.int32(); leader < 0 {
+				t.leaderless = append(t.leaderless, index)
+			}
+			r.int32Array() // replica_nodes
+			r.int32Array() // isr_nodes
+		}
+		md.topics[name] = t
+	}
+	return md, r.err
+}
+
+// topicProblems describes every requested topic that is missing or has partitions
+// without a leader.
+func (md kafkaMetadata) topicProblems(topics []string) []string {
+	var problems []string
+	for _, name := range topics {
+		t, ok := md.topics[name]
+		switch {
+		case !ok || t.errorCode == kafkaErrUnknownTopicOrPartition:
+			problems = append(problems, fmt.Sprintf("topic %s does not exist", name))
+		case t.errorCode != 0:
+			problems = append(problems, fmt.Sprintf("topic %s: error code %d", name, t.errorCode))
+		case len(t.leaderless) > 0:
+			slices.Sort(t.leaderless)
+			ids := make([]string, len(t.leaderless))
+			for i, p := range t.leaderless {
+				ids[i] = fmt.Sprint(p)
+			}
+			problems = append(problems, fmt.Sprintf("topic %s has no leader for partitions %s", name, strings.Join(ids, ", ")))
+		}
+	}
+	return problems
+}
+
+func appendKafkaString(b []byte, s string) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// kafkaReader decodes big-endian Kafka primitives and records the first error.
+type kafkaReader struct {
+	b   []byte
+	err error
+}
+
+func (r *kafkaReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || len(r.b) < n {
+		r.err = errorf(ReasonProtocolError, "truncated response")
+		return nil
+	}
+	v := r.b[:n]
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *kafkaReader) bool() bool {
+	b := r.next(1)
+	return b != nil && b[0] != 0
+}
+
+func (r *kafkaReader) int16() int16 {
+	if b := r.next(2); b != nil {
+		return int16(binary.BigEndian.Uint16(b))
+	}
+	return 0
+}
+
+func (r *kafkaReader) int32() int32 {
+	if b := r.next(4); b != nil {
+		return int32(binary.BigEndian.Uint32(b))
+	}
+	return 0
+}
+
+// string reads a nullable string; null is returned as "".
+func (r *kafkaReader) string() string {
+	n := int(r.int16())
+	if n < 0 {
+		return ""
+	}
+	return string(r.next(n))
+}
+
+func (r *kafkaReader) int32Array() {
+	for n := r.int32(); n > 0 && r.err == nil; n-- {
+		r.int32()
+	}
+}
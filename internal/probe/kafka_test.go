@@ -0,0 +1,170 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// fakeTopic describes a topic in a fake Metadata response. A negative leader marks
+// a leaderless partition.
+type fakeTopic struct {
+	name      string
+	errorCode int16
+	leaders   []int32
+}
+
+func be16(b []byte, v int16) []byte { return binary.BigEndian.AppendUint16(b, uint16(v)) }
+func be32(b []byte, v int32) []byte { return binary.BigEndian.AppendUint32(b, uint32(v)) }
+
+func apiVersionsResponse(metadataMin, metadataMax int16) []byte {
+	b := be16(nil, 0)
+	b = be32(b, 2)
+	b = be16(be16(be16(b, kafkaAPIKeyMetadata), metadataMin), metadataMax)
+	b = be16(be16(be16(b, kafkaAPIKeyAPIVersions), 0), 3)
+	return b
+}
+
+func metadataResponse(controllerID int32, topics []fakeTopic) []byte {
+	b := be32(nil, 0) // throttle_time_ms
+	b = be32(b, 1)    // one broker
+	b = be32(b, 1)
+	b = appendKafkaString(b, "kafka-0.kafka")
+	b = be32(b, 9092)
+	b = be16(b, -1) // rack
+	b = appendKafkaString(b, "cluster-id")
+	b = be32(b, controllerID)
+	b = be32(b, int32(len(topics)))
+	for _, t := range topics {
+		b = be16(b, t.errorCode)
+		b = appendKafkaString(b, t.name)
+		b = append(b, 0)
+		b = be32(b, int32(len(t.leaders)))
+		for i, leader := range t.leaders {
+			b = be16(b, 0)
+			b = be32(b, int32(i))
+			b = be32(b, leader)
+			b = be32(be32(b, 1), 1) // replica_nodes
+			b = be32(be32(b, 1), 1) // isr_nodes
+		}
+	}
+	return b
+}
+
+// startFakeKafka accepts one connection and answers ApiVersions and Metadata requests.
+// The requested topics are sent to the returned channel.
+func startFakeKafka(apiVersions, metadata []byte) (string, <-chan []string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	requested := make(chan []string, 1)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for {
+			size := make([]byte, 4)
+			if _, err := io.ReadFull(conn, size); err != nil {
+				return
+			}
+			req := make([]byte, binary.BigEndian.Uint32(size))
+			if _, err := io.ReadFull(conn, req); err != nil {
+				return
+			}
+			r := &kafkaReader{b: req}
+			apiKey := r.int16()
+			r.int16()
+			correlationID := r.int32()
+			r.string()
+
+			body := apiVersions
+			if apiKey == kafkaAPIKeyMetadata {
+				var topics []string
+				for n := r.int32(); n > 0; n-- {
+					topics = append(topics, r.string())
+				}
+				requested <- topics
+				body = metadata
+			}
+			resp := be32(nil, int32(4+len(body)))
+			resp = be32(resp, correlationID)
+			if _, err := conn.Write(append(resp, body...)); err != nil {
+				return
+			}
+		}
+	}()
+	return lis.Addr().String(), requested
+}
+
+var _ = Describe("Kafka", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	It("should be ready once the broker reports an active controller", func() {
+		addr, _ := startFakeKafka(apiVersionsResponse(0, 12), metadataResponse(1, nil))
+		detail, err := Kafka(probeCtx(), addr, corev1alpha1.KafkaProbe{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("1 broker(s), controller 1"))
+	})
+
+	It("should report ControllerNotAvailable before the quorum has formed", func() {
+		addr, _ := startFakeKafka(apiVersionsResponse(0, 12), metadataResponse(-1, nil))
+		_, err := Kafka(probeCtx(), addr, corev1alpha1.KafkaProbe{})
+		Expect(Reason(err)).To(Equal(ReasonControllerNotAvailable))
+	})
+
+	It("should fail when the broker does not support Metadata v4", func() {
+		addr, _ := startFakeKafka(apiVersionsResponse(0, 2), nil)
+		_, err := Kafka(probeCtx(), addr, corev1alpha1.KafkaProbe{})
+		Expect(Reason(err)).To(Equal(ReasonProtocolError))
+	})
+
+	It("should be ready when every requested topic has leaders", func() {
+		addr, requested := startFakeKafka(apiVersionsResponse(0, 12), metadataResponse(1, []fakeTopic{
+			{name: "orders", leaders: []int32{1, 1, 1}},
+		}))
+		_, err := Kafka(probeCtx(), addr, corev1alpha1.KafkaProbe{Topics: []string{"orders"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-requested).To(Equal([]string{"orders"}))
+	})
+
+	It("should name missing topics and leaderless partitions", func() {
+		addr, _ := startFakeKafka(apiVersionsResponse(0, 12), metadataResponse(1, []fakeTopic{
+			{name: "orders", leaders: []int32{1, -1, 1, -1}},
+			{name: "payments", errorCode: kafkaErrUnknownTopicOrPartition},
+			{name: "audit", leaders: []int32{1}},
+		}))
+		_, err := Kafka(probeCtx(), addr, corev1alpha1.KafkaProbe{Topics: []string{"orders", "payments", "audit"}})
+		Expect(Reason(err)).To(Equal(ReasonTopicsNotReady))
+		Expect(err).To(MatchError("topic orders has no leader for partitions 1, 3; topic payments does not exist"))
+	})
+})
@@ -0,0 +1,260 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the MongoDB probe.
+const (
+	ReasonNotWritablePrimary = "NotWritablePrimary"
+	ReasonReplicaSetMismatch = "ReplicaSetMismatch"
+)
+
+const (
+	mongoOpMsg              = 2013
+	mongoMaxMessageSize     = 16 << 20
+	mongoCommandNotFound    = 59
+	mongoHeaderSize         = 16
+	mongoSectionKindBody    = 0
+	mongoDefaultCommandName = "hello"
+	mongoLegacyCommandName  = "isMaster"
+)
+
+// MongoDB sends the hello command over OP_MSG to the server on addr. When
+// spec.RequireWritablePrimary is set, the server must report isWritablePrimary, so a
+// replica set that is still electing a primary is not ready. When spec.ReplicaSet is
+// set, the server must belong to that replica set. Servers that predate hello are
+// asked with the legacy isMaster command instead.
+func MongoDB(ctx context.Context, addr string, spec corev1alpha1.MongoDBProbe) (string, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	mc := &mongoConn{conn: conn}
+	reply, err := mc.command(mongoDefaultCommandName)
+	if err == nil && reply.number("ok") != 1 && reply.number("code") == mongoCommandNotFound {
+		reply, err = mc.command(mongoLegacyCommandName)
+	}
+	if err != nil {
+		return "", err
+	}
+	if reply.number("ok") != 1 {
+		msg, _ := reply["errmsg"].(string)
+		return "", errorf(ReasonServerError, "hello failed: %s", msg)
+	}
+
+	writable, _ := reply["isWritablePrimary"].(bool)
+	if legacy, ok := reply["ismaster"].(bool); ok {
+		writable = legacy
+	}
+	setName, _ := reply["setName"].(string)
+	secondary, _ := reply["secondary"].(bool)
+
+	state := "standalone"
+	switch {
+	case reply["msg"] == "isdbgrid":
+		state = "mongos"
+	case setName != "" && writable:
+		state = "primary of replica set " + setName
+	case setName != "" && secondary:
+		state = "secondary of replica set " + setName
+	case setName != "":
+		state = "member of replica set " + setName + " without a primary role"
+	}
+
+	if spec.ReplicaSet != "" && setName != spec.ReplicaSet {
+		return "", errorf(ReasonReplicaSetMismatch, "server is %s, expected replica set %s", state, spec.ReplicaSet)
+	}
+	if spec.RequireWritablePrimary && !writable {
+		return "", errorf(ReasonNotWritablePrimary, "server is %s, not a writable primary", state)
+	}
+	return state, nil
+}
+
+// mongoConn is a minimal MongoDB wire protocol connection.
+type mongoConn struct {
+	conn      net.Conn
+	requestID int32
+}
+
+// command runs {<name>: 1, $db: "admin"} and returns the reply document.
+func (c *mongoConn) command(name string) (bsonDoc, error) {
+	var body []byte
+	body = appendBSONInt32(body, name, 1)
+	body = appendBSONString(body, "$db", "admin")
+	doc := binary.LittleEndian.AppendUint32(nil, uint32(4+len(body)+1))
+	doc = append(append(doc, body...), 0)
+
+	c.requestID++
+	msg := make([]byte, mongoHeaderSize, mongoHeaderSize+5+len(doc))
+	binary.LittleEndian.PutUint32(msg[4:8], uint32(c.requestID))
+	binary.LittleEndian.PutUint32(msg[12:16], mongoOpMsg)
+	msg = binary.LittleEndian.AppendUint32(msg, 0) // flagBits
+	msg = append(msg, mongoSectionKindBody)
+	msg = append(msg, doc...)
+	binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)))
+	if _, err := c.conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	hdr := make([]byte, mongoHeaderSize)
+	if _, err := io.ReadFull(c.conn, hdr); err != nil {
+		return nil, err
+	}
+	size := int(binary.LittleEndian.Uint32(hdr[0:4]))
+	if size < mongoHeaderSize+5 || size > mongoMaxMessageSize {
+		return nil, errorf(ReasonProtocolError, "invalid message length %d", size)
+	}
+	if op := binary.LittleEndian.Uint32(hdr[12:16]); op != mongoOpMsg {
+		return nil, errorf(ReasonProtocolError, "unexpected opcode %d", op)
+	}
+	payload := make([]byte, size-mongoHeaderSize)
+	if _, err := io.ReadFull(c.conn, payload); err != nil {
+		return nil, err
+	}
+	if payload[4] != mongoSectionKindBody {
+		return nil, errorf(ReasonProtocolError, "unexpected section kind %d", payload[4])
+	}
+	return parseBSON(payload[5:])
+}
+
+func appendBSONInt32(b []byte, name string, v int32) []byte {
+	b = append(b, 0x10)
+	b = append(append(b, name...), 0)
+	return binary.LittleEndian.AppendUint32(b, uint32(v))
+}
+
+func appendBSONString(b []byte, name, v string) []byte {
+	b = append(b, 0x02)
+	b = append(append(b, name...), 0)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(v)+1))
+	return append(append(b, v...), 0)
+}
+
+// bsonDoc holds the top-level scalar fields of a BSON document: doubles and
+// integers as float64, strings and booleans as is. Other values are skipped.
+type bsonDoc map[string]any
+
+// number returns a numeric field as float64, or NaN when it is absent.
+func (d bsonDoc) number(name string) float64 {
+	if v, ok := d[name].(float64); ok {
+		return v
+	}
+	return math.NaN()
+}
+
+// parseBSON decodes the top-level fields of a BSON document.
+func parseBSON(b []byte) (bsonDoc, error) {
+	malformed := errorf(ReasonProtocolError, "malformed BSON document")
+	if len(b) < 5 {
+		return nil, malformed
+	}
+	n := int(binary.LittleEndian.Uint32(b[0:4]))
+	if n < 5 || n > len(b) {
+		return nil, malformed
+	}
+	b = b[4 : n-1]
+
+	doc := bsonDoc{}
+	for len(b) > 0 {
+		typ := b[0]
+		end := bytes.IndexByte(b[1:], 0)
+		if end < 0 {
+			return nil, malformed
+		}
+		name := string(b[1 : 1+end])
+		b = b[2+end:]
+
+		size := -1
+		switch typ {
+		case 0x01: // double
+			if len(b) >= 8 {
+				doc[name] = math.Float64frombits(binary.LittleEndian.Uint64(b))
+			}
+			size = 8
+		case 0x02, 0x0d, 0x0e: // string, JavaScript code, symbol
+			if len(b) >= 4 {
+				size = 4 + int(binary.LittleEndian.Uint32(b))
+				if typ == 0x02 && size <= len(b) && size > 4 {
+					doc[name] = string(b[4 : size-1])
+				}
+			}
+		case 0x03, 0x04, 0x0f: // document, array, code with scope
+			if len(b) >= 4 {
+				size = int(binary.LittleEndian.Uint32(b))
+			}
+		case 0x05: // binary
+			if len(b) >= 4 {
+				size = 5 + int(binary.LittleEndian.Uint32(b))
+			}
+		case 0x06, 0x0a, 0x7f, 0xff: // undefined, null, max key, min key
+			size = 0
+		case 0x07: // ObjectId
+			size = 12
+		case 0x08: // boolean
+			if len(b) >= 1 {
+				doc[name] = b[0] != 0
+			}
+			size = 1
+		case 0x09, 0x11: // datetime, timestamp
+			size = 8
+		case 0x10: // int32
+			if len(b) >= 4 {
+				doc[name] = float64(int32(binary.LittleEndian.Uint32(b)))
+			}
+			size = 4
+		case 0x12: // int64
+			if len(b) >= 8 {
+				doc[name] = float64(int64(binary.LittleEndian.Uint64(b)))
+			}
+			size = 8
+		case 0x13: // decimal128
+			size = 16
+		case 0x0b: // regular expression: two C strings
+			first := bytes.IndexByte(b, 0)
+			if first >= 0 {
+				if second := bytes.IndexByte(b[first+1:], 0); second >= 0 {
+					size = first + second + 2
+				}
+			}
+		case 0x0c: // DBPointer
+			if len(b) >= 4 {
+				size = 4 + int(binary.LittleEndian.Uint32(b)) + 12
+			}
+		}
+		if size < 0 || size > len(b) {
+			return nil, malformed
+		}
+		b = b[size:]
+	}
+	return doc, nil
+}
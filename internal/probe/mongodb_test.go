@@ -0,0 +1,171 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// bsonField is a field of a BSON document built by encodeBSON.
+type bsonField struct {
+	name  string
+	value any
+}
+
+// encodeBSON encodes float64, bool, string and nested []bsonField values.
+func encodeBSON(fields ...bsonField) []byte {
+	var body []byte
+	for _, f := range fields {
+		switch v := f.value.(type) {
+		case float64:
+			body = append(append(append(body, 0x01), f.name...), 0)
+			body = binary.LittleEndian.AppendUint64(body, math.Float64bits(v))
+		case bool:
+			body = append(append(append(body, 0x08), f.name...), 0)
+			b := byte(0)
+			if v {
+				b = 1
+			}
+			body = append(body, b)
+		case string:
+			body = appendBSONString(body, f.name, v)
+		case []bsonField:
+			body = append(append(append(body, 0x03), f.name...), 0)
+			body = append(body, encodeBSON(v...)...)
+		}
+	}
+	doc := binary.LittleEndian.AppendUint32(nil, uint32(4+len(body)+1))
+	return append(append(doc, body...), 0)
+}
+
+// startFakeMongo accepts one connection and answers each OP_MSG command with the
+// document returned by reply for the command's name.
+func startFakeMongo(reply func(command string) []byte) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		for {
+			hdr := make([]byte, mongoHeaderSize)
+			if _, err := io.ReadFull(conn, hdr); err != nil {
+				return
+			}
+			payload := make([]byte, binary.LittleEndian.Uint32(hdr[0:4])-mongoHeaderSize)
+			if _, err := io.ReadFull(conn, payload); err != nil {
+				return
+			}
+			// The command name is the first element of the body document.
+			body := payload[5+4:]
+			name := string(body[1 : 1+bytes.IndexByte(body[1:], 0)])
+
+			doc := reply(name)
+			msg := make([]byte, mongoHeaderSize)
+			binary.LittleEndian.PutUint32(msg[8:12], binary.LittleEndian.Uint32(hdr[4:8]))
+			binary.LittleEndian.PutUint32(msg[12:16], mongoOpMsg)
+			msg = binary.LittleEndian.AppendUint32(msg, 0)
+			msg = append(msg, mongoSectionKindBody)
+			msg = append(msg, doc...)
+			binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)))
+			if _, err := conn.Write(msg); err != nil {
+				return
+			}
+		}
+	}()
+	return lis.Addr().String()
+}
+
+var _ = Describe("MongoDB", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	replicaSetMember := func(primary bool) func(string) []byte {
+		return func(string) []byte {
+			return encodeBSON(
+				bsonField{"topologyVersion", []bsonField{{"counter", float64(3)}}},
+				bsonField{"isWritablePrimary", primary},
+				bsonField{"secondary", !primary},
+				bsonField{"setName", "rs0"},
+				bsonField{"ok", float64(1)},
+			)
+		}
+	}
+
+	It("should be ready for a standalone server without options", func() {
+		addr := startFakeMongo(func(string) []byte {
+			return encodeBSON(bsonField{"isWritablePrimary", true}, bsonField{"ok", float64(1)})
+		})
+		detail, err := MongoDB(probeCtx(), addr, corev1alpha1.MongoDBProbe{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("standalone"))
+	})
+
+	It("should be ready on the primary when requireWritablePrimary is set", func() {
+		addr := startFakeMongo(replicaSetMember(true))
+		spec := corev1alpha1.MongoDBProbe{RequireWritablePrimary: true, ReplicaSet: "rs0"}
+		detail, err := MongoDB(probeCtx(), addr, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("primary of replica set rs0"))
+	})
+
+	It("should report NotWritablePrimary while the replica set has no primary", func() {
+		addr := startFakeMongo(replicaSetMember(false))
+		_, err := MongoDB(probeCtx(), addr, corev1alpha1.MongoDBProbe{RequireWritablePrimary: true})
+		Expect(Reason(err)).To(Equal(ReasonNotWritablePrimary))
+		Expect(err.Error()).To(ContainSubstring("secondary of replica set rs0"))
+	})
+
+	It("should report ReplicaSetMismatch for a different replica set", func() {
+		addr := startFakeMongo(replicaSetMember(true))
+		_, err := MongoDB(probeCtx(), addr, corev1alpha1.MongoDBProbe{ReplicaSet: "rs1"})
+		Expect(Reason(err)).To(Equal(ReasonReplicaSetMismatch))
+	})
+
+	It("should fall back to isMaster on servers without hello", func() {
+		addr := startFakeMongo(func(command string) []byte {
+			if command == "hello" {
+				return encodeBSON(
+					bsonField{"ok", float64(0)},
+					bsonField{"errmsg", "no such command: 'hello'"},
+					bsonField{"code", float64(mongoCommandNotFound)},
+				)
+			}
+			return encodeBSON(bsonField{"ismaster", true}, bsonField{"setName", "rs0"}, bsonField{"ok", float64(1)})
+		})
+		_, err := MongoDB(probeCtx(), addr, corev1alpha1.MongoDBProbe{RequireWritablePrimary: true})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
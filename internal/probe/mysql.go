@@ -0,0 +1,431 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec // required by mysql_native_password and RSA-OAEP in caching_sha2_password
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+const (
+	mysqlClientLongPassword     = 0x00000001
+	mysqlClientConnectWithDB    = 0x00000008
+	mysqlClientProtocol41       = 0x00000200
+	mysqlClientTransactions     = 0x00002000
+	mysqlClientSecureConnection = 0x00008000
+	mysqlClientPluginAuth       = 0x00080000
+
+	mysqlCharsetUTF8MB4 = 45
+	mysqlMaxPacketSize  = 1 << 24
+	mysqlDefaultQuery   = "SELECT 1"
+
+	mysqlNativePassword = "mysql_native_password"
+	mysqlCachingSHA2    = "caching_sha2_password"
+)
+
+// MySQL reads the initial handshake packet sent by a MySQL or MariaDB server on addr
+// and returns the server version reported in it. Without credentials the server is
+// ready once it sends the handshake; an error packet instead (e.g. "Too many
+// connections") keeps it not ready. With credentials the probe also authenticates and
+// runs SELECT 1.
+func MySQL(ctx context.Context, addr string, spec corev1alpha1.MySQLProbe, secrets SecretReader) (string, error) {
+	var creds *authCredentials
+	if spec.CredentialsSecretRef != nil {
+		c, err := readCredentials(ctx, secrets, spec.CredentialsSecretRef)
+		if err != nil {
+			return "", err
+		}
+		creds = &c
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	mc := &mysqlConn{conn: conn, r: bufio.NewReader(conn)}
+	hs, err := mc.readHandshake()
+	if err != nil {
+		return "", err
+	}
+	detail := "server version " + hs.serverVersion
+	if creds == nil {
+		return detail, nil
+	}
+
+	if err := mc.authenticate(hs, *creds, spec.Database); err != nil {
+		return "", err
+	}
+	if err := mc.query(mysqlDefaultQuery); err != nil {
+		return "", err
+	}
+	mc.seq = 0
+	_ = mc.writePacket([]byte{0x01}) // COM_QUIT
+	return detail, nil
+}
+
+// mysqlConn is a minimal MySQL client connection.
+type mysqlConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+	seq  byte
+}
+
+// readPacket reads one packet and tracks its sequence id.
+func (c *mysqlConn) readPacket() ([]byte, error) {
+	hdr := make([]byte, 4)
+	if _, err := io.ReadFull(c.r, hdr); err != nil {
+		return nil, err
+	}
+	n := int(hdr[0]) | int(hdr[1])<<8 | int(hdr[2])<<16
+	c.seq = hdr[3] + 1
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// writePacket writes payload as a single packet with the next sequence id.
+func (c *mysqlConn) writePacket(payload []byte) error {
+	if len(payload) >= mysqlMaxPacketSize-1 {
+		return errorf(ReasonProtocolError, "packet too large")
+	}
+	n := len(payload)
+	msg := append([]byte{byte(n), byte(n >> 8), byte(n >> 16), c.seq}, payload...)
+	c.seq++
+	_, err := c.conn.Write(msg)
+	return err
+}
+
+// mysqlHandshake holds the fields of the initial handshake packet the probe uses.
+type mysqlHandshake struct {
+	serverVersion string
+	capabilities  uint32
+	authData      []byte
+	authPlugin    string
+}
+
+// readHandshake reads and decodes the server's initial handshake packet (protocol 10).
+func (c *mysqlConn) readHandshake() (mysqlHandshake, error) {
+	var hs mysqlHandshake
+	p, err := c.readPacket()
+	if err != nil {
+		return hs, err
+	}
+	if len(p) > 0 && p[0] == 0xff {
+		return hs, parseMySQLError(p).probeError()
+	}
+	if len(p) == 0 || p[0] != 10 {
+		return hs, errorf(ReasonProtocolError, "unsupported handshake protocol version")
+	}
+
+	p = p[1:]
+	end := bytes.IndexByte(p, 0)
+	if end < 0 {
+		return hs, errorf(ReasonProtocolError, "malformed handshake packet")
+	}
+	hs.serverVersion = string(p[:end])
+	p = p[end+1:]
+
+	// connection id (4), auth-plugin-data-part-1 (8), filler (1), capability flags (2)
+	if len(p) < 15 {
+		return hs, errorf(ReasonProtocolError, "short handshake packet")
+	}
+	hs.authData = append(hs.authData, p[4:12]...)
+	hs.capabilities = uint32(binary.LittleEndian.Uint16(p[13:15]))
+	p = p[15:]
+
+	// character set (1), status flags (2), capability flags upper (2),
+	// auth-plugin-data length (1), reserved (10)
+	if len(p) < 16 {
+		return hs, nil
+	}
+	hs.capabilities |= uint32(binary.LittleEndian.Uint16(p[3:5])) << 16
+	authLen := int(p[5])
+	p = p[16:]
+
+	if hs.capabilities&mysqlClientSecureConnection != 0 {
+		n := max(13, authLen-8)
+		if len(p) < n {
+			return hs, errorf(ReasonProtocolError, "short handshake auth data")
+		}
+		// The last byte of auth-plugin-data-part-2 is a NUL terminator.
+		hs.authData = append(hs.authData, bytes.TrimRight(p[:n], "\x00")...)
+		p = p[n:]
+	}
+	if hs.capabilities&mysqlClientPluginAuth != 0 {
+		hs.authPlugin = string(bytes.TrimRight(p, "\x00"))
+	}
+	return hs, nil
+}
+
+// authenticate sends a HandshakeResponse41 and completes the authentication exchange,
+// including auth switch requests and caching_sha2_password full authentication.
+func (c *mysqlConn) authenticate(hs mysqlHandshake, creds authCredentials, database string) error {
+	if hs.capabilities&mysqlClientProtocol41 == 0 {
+		return errorf(ReasonProtocolError, "server does not support protocol 4.1")
+	}
+	plugin := hs.authPlugin
+	if plugin == "" {
+		plugin = mysqlNativePassword
+	}
+	authResp, err := mysqlScramble(plugin, creds.password, hs.authData)
+	if err != nil {
+		return err
+	}
+
+	flags := uint32(mysqlClientLongPassword | mysqlClientProtocol41 | mysqlClientTransactions |
+		mysqlClientSecureConnection | mysqlClientPluginAuth)
+	if database != "" {
+		flags |= mysqlClientConnectWithDB
+	}
+	flags &= hs.capabilities | mysqlClientConnectWithDB
+
+	var b []byte
+	b = binary.LittleEndian.AppendUint32(b, flags)
+	b = binary.LittleEndian.AppendUint32(b, mysqlMaxPacketSize)
+	b = append(b, mysqlCharsetUTF8MB4)
+	b = append(b, make([]byte, 23)...)
+	b = append(b, creds.username...)
+	b = append(b, 0)
+	b = append(b, byte(len(authResp)))
+	b = append(b, authResp...)
+	if database != "" {
+		b = append(b, database...)
+		b = append(b, 0)
+	}
+	if flags&mysqlClientPluginAuth != 0 {
+		b = append(b, plugin...)
+		b = append(b, 0)
+	}
+	if err := c.writePacket(b); err != nil {
+		return err
+	}
+
+	authData := hs.authData
+	for {
+		p, err := c.readPacket()
+		if err != nil {
+			return err
+		}
+		if len(p) == 0 {
+			return errorf(ReasonProtocolError, "empty packet during authentication")
+		}
+		switch p[0] {
+		case 0x00: // OK
+			return nil
+		case 0xff: // ERR
+			return parseMySQLError(p).probeError()
+		case 0xfe: // AuthSwitchRequest
+			name, data, _ := bytes.Cut(p[1:], []byte{0})
+			plugin = string(name)
+			authData = bytes.TrimRight(data, "\x00")
+			if authResp, err = mysqlScramble(plugin, creds.password, authData); err != nil {
+				return err
+			}
+			if err := c.writePacket(authResp); err != nil {
+				return err
+			}
+		case 0x01: // AuthMoreData
+			if plugin != mysqlCachingSHA2 || len(p) < 2 {
+				return errorf(ReasonProtocolError, "unexpected AuthMoreData for %s", plugin)
+			}
+			switch {
+			case p[1] == 3: // fast authentication succeeded, OK follows
+			case p[1] == 4: // full authentication: request the server's RSA public key
+				if err := c.writePacket([]byte{0x02}); err != nil {
+					return err
+				}
+			case p[1] == '-': // PEM-encoded public key
+				enc, err := mysqlEncryptPassword(creds.password, authData, p[1:])
+				if err != nil {
+					return err
+				}
+				if err := c.writePacket(enc); err != nil {
+					return err
+				}
+			default:
+				return errorf(ReasonProtocolError, "unexpected caching_sha2_password state %d", p[1])
+			}
+		default:
+			return errorf(ReasonProtocolError, "unexpected packet 0x%02x during authentication", p[0])
+		}
+	}
+}
+
+// query runs q with COM_QUERY and consumes the result set.
+func (c *mysqlConn) query(q string) error {
+	c.seq = 0
+	if err := c.writePacket(append([]byte{0x03}, q...)); err != nil {
+		return err
+	}
+	p, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if len(p) > 0 && p[0] == 0xff {
+		return errorf(ReasonQueryFailed, "query failed: %s", parseMySQLError(p))
+	}
+	if len(p) > 0 && p[0] == 0x00 {
+		return nil // OK packet, no result set
+	}
+
+	// Column definitions and rows are each terminated by an EOF packet.
+	for eofs := 0; eofs < 2; {
+		p, err := c.readPacket()
+		if err != nil {
+			return err
+		}
+		switch {
+		case len(p) > 0 && p[0] == 0xff:
+			return errorf(ReasonQueryFailed, "query failed: %s", parseMySQLError(p))
+		case len(p) > 0 && len(p) < 9 && p[0] == 0xfe:
+			eofs++
+		}
+	}
+	return nil
+}
+
+// mysqlScramble computes the auth response for the given authentication plugin.
+func mysqlScramble(plugin, password string, nonce []byte) ([]byte, error) {
+	if password == "" {
+		return nil, nil
+	}
+	switch plugin {
+	case mysqlNativePassword:
+		// SHA1(password) XOR SHA1(nonce + SHA1(SHA1(password)))
+		stage1 := sha1.Sum([]byte(password)) //nolint:gosec
+		stage2 := sha1.Sum(stage1[:])        //nolint:gosec
+		h := sha1.New()                      //nolint:gosec
+		h.Write(nonce)
+		h.Write(stage2[:])
+		return xorBytes(stage1[:], h.Sum(nil)), nil
+	case mysqlCachingSHA2:
+		// SHA256(password) XOR SHA256(SHA256(SHA256(password)) + nonce)
+		stage1 := sha256.Sum256([]byte(password))
+		stage2 := sha256.Sum256(stage1[:])
+		h := sha256.New()
+		h.Write(stage2[:])
+		h.Write(nonce)
+		return xorBytes(stage1[:], h.Sum(nil)), nil
+	default:
+		return nil, errorf(ReasonAuthenticationFailed, "unsupported authentication plugin %q", plugin)
+	}
+}
+
+// mysqlEncryptPassword encrypts the NUL-terminated password, XORed with the nonce,
+// with the server's RSA public key as caching_sha2_password requires on
+// connections without TLS. A server that sent no nonce is reported as a
+// ProtocolError.
+func mysqlEncryptPassword(password string, nonce, pemKey []byte) ([]byte, error) {
+	if len(nonce) == 0 {
+		return nil, errorf(ReasonProtocolError, "empty auth data in handshake")
+	}
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return nil, errorf(ReasonAuthenticationFailed, "invalid server public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errorf(ReasonAuthenticationFailed, "invalid server public key: %v", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, errorf(ReasonAuthenticationFailed, "server public key is not an RSA key")
+	}
+	plain := append([]byte(password), 0)
+	for i := range plain {
+		plain[i] ^= nonce[i%len(nonce)]
+	}
+	enc, err := rsa.EncryptOAEP(sha1.New(), rand.Reader, rsaKey, plain, nil) //nolint:gosec
+	if err != nil {
+		return nil, errorf(ReasonAuthenticationFailed, "%v", err)
+	}
+	return enc, nil
+}
+
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+// mysqlError is a decoded ERR packet.
+type mysqlError struct {
+	code     uint16
+	sqlState string
+	message  string
+}
+
+func (e mysqlError) String() string {
+	if e.sqlState == "" {
+		return fmt.Sprintf("ERROR %d: %s", e.code, e.message)
+	}
+	return fmt.Sprintf("ERROR %d (%s): %s", e.code, e.sqlState, e.message)
+}
+
+// parseMySQLError decodes an ERR packet. The SQL state marker is absent in errors
+// sent in place of the initial handshake.
+func parseMySQLError(p []byte) mysqlError {
+	var e mysqlError
+	if len(p) < 3 {
+		return e
+	}
+	e.code = binary.LittleEndian.Uint16(p[1:3])
+	p = p[3:]
+	if len(p) >= 6 && p[0] == '#' {
+		e.sqlState = string(p[1:6])
+		p = p[6:]
+	}
+	e.message = string(p)
+	return e
+}
+
+// probeError maps the MySQL error code to a probe reason.
+func (e mysqlError) probeError() error {
+	switch e.code {
+	case 1040: // ER_CON_COUNT_ERROR
+		return errorf(ReasonTooManyConnections, "%s", e)
+	case 1044, 1045, 1251, 1698: // ER_DBACCESS_DENIED_ERROR, ER_ACCESS_DENIED_ERROR, ER_NOT_SUPPORTED_AUTH_MODE, ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+		return errorf(ReasonAuthenticationFailed, "%s", e)
+	case 1053: // ER_SERVER_SHUTDOWN
+		return errorf(ReasonDatabaseShuttingDown, "%s", e)
+	default:
+		return errorf(ReasonServerError, "%s", e)
+	}
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" //nolint:gosec
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var mysqlTestNonce = []byte("abcdefghijklmnopqrst")
+
+// mysqlBackend is the server side of a fake MySQL connection.
+type mysqlBackend struct {
+	conn net.Conn
+	seq  byte
+}
+
+func (b *mysqlBackend) send(payload []byte) {
+	n := len(payload)
+	_, _ = b.conn.Write(append([]byte{byte(n), byte(n >> 8), byte(n >> 16), b.seq}, payload...))
+	b.seq++
+}
+
+func (b *mysqlBackend) receive() []byte {
+	hdr := make([]byte, 4)
+	if _, err := io.ReadFull(b.conn, hdr); err != nil {
+		return nil
+	}
+	b.seq = hdr[3] + 1
+	payload := make([]byte, int(hdr[0])|int(hdr[1])<<8|int(hdr[2])<<16)
+	_, _ = io.ReadFull(b.conn, payload)
+	return payload
+}
+
+func (b *mysqlBackend) handshake(version string) {
+	p := []byte{10}
+	p = append(p, version...)
+	p = append(p, 0)
+	p = binary.LittleEndian.AppendUint32(p, 42) // connection id
+	p = append(p, mysqlTestNonce[:8]...)
+	p = append(p, 0)
+	caps := uint32(mysqlClientLongPassword | mysqlClientConnectWithDB | mysqlClientProtocol41 |
+		mysqlClientTransactions | mysqlClientSecureConnection | mysqlClientPluginAuth)
+	p = binary.LittleEndian.AppendUint16(p, uint16(caps))
+	p = append(p, mysqlCharsetUTF8MB4, 2, 0)
+	p = binary.LittleEndian.AppendUint16(p, uint16(caps>>16))
+	p = append(p, byte(len(mysqlTestNonce)+1))
+	p = append(p, make([]byte, 10)...)
+	p = append(p, mysqlTestNonce[8:]...)
+	p = append(p, 0)
+	p = append(p, mysqlNativePassword...)
+	b.send(append(p, 0))
+}
+
+func (b *mysqlBackend) fail(code uint16, sqlState, message string) {
+	p := binary.LittleEndian.AppendUint16([]byte{0xff}, code)
+	if sqlState != "" {
+		p = append(p, '#')
+		p = append(p, sqlState...)
+	}
+	b.send(append(p, message...))
+}
+
+func (b *mysqlBackend) ok() {
+	b.send([]byte{0x00, 0, 0, 2, 0, 0, 0})
+}
+
+// startFakeMySQL accepts one connection and hands it to handle.
+func startFakeMySQL(handle func(b *mysqlBackend)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	go func() {
+		defer GinkgoRecover()
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		handle(&mysqlBackend{conn: conn})
+	}()
+	return lis.Addr().String()
+}
+
+// parseHandshakeResponse extracts the user, auth response and database from a
+// HandshakeResponse41 packet.
+func parseHandshakeResponse(p []byte) (user string, auth []byte, database string) {
+	flags := binary.LittleEndian.Uint32(p[0:4])
+	p = p[32:]
+	end := bytes.IndexByte(p, 0)
+	user, p = string(p[:end]), p[end+1:]
+	auth, p = p[1:1+int(p[0])], p[1+int(p[0]):]
+	if flags&mysqlClientConnectWithDB != 0 {
+		end = bytes.IndexByte(p, 0)
+		database = string(p[:end])
+	}
+	return user, auth, database
+}
+
+// checkNativePassword verifies a mysql_native_password scramble the way the server
+// does, knowing only SHA1(SHA1(password)).
+func checkNativePassword(password string, scramble []byte) bool {
+	stage1 := sha1.Sum([]byte(password)) //nolint:gosec
+	stored := sha1.Sum(stage1[:])        //nolint:gosec
+	h := sha1.New()                      //nolint:gosec
+	h.Write(mysqlTestNonce)
+	h.Write(stored[:])
+	candidate := xorBytes(scramble, h.Sum(nil))
+	got := sha1.Sum(candidate) //nolint:gosec
+	return len(scramble) == sha1.Size && got == stored
+}
+
+var _ = Describe("MySQL", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	credsRef := &corev1alpha1.SecretCredentialsRef{Name: "mysql"}
+	secrets := mapSecrets{"mysql/username": "app", "mysql/password": "s3cret"}
+
+	Context("without credentials", func() {
+		It("should be ready once the handshake arrives and report the server version", func() {
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				b.handshake("8.0.36")
+			})
+			detail, err := MySQL(probeCtx(), addr, corev1alpha1.MySQLProbe{}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(detail).To(Equal("server version 8.0.36"))
+		})
+
+		It("should report the error packet sent instead of the handshake", func() {
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				b.fail(1040, "", "Too many connections")
+			})
+			_, err := MySQL(probeCtx(), addr, corev1alpha1.MySQLProbe{}, nil)
+			Expect(err).To(MatchError("ERROR 1040: Too many connections"))
+			Expect(Reason(err)).To(Equal(ReasonTooManyConnections))
+		})
+
+		It("should report ServerError for other error packets", func() {
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				b.fail(1129, "", "Host '10.0.0.7' is blocked because of many connection errors")
+			})
+			_, err := MySQL(probeCtx(), addr, corev1alpha1.MySQLProbe{}, nil)
+			Expect(Reason(err)).To(Equal(ReasonServerError))
+			Expect(err.Error()).To(ContainSubstring("ERROR 1129"))
+		})
+	})
+
+	Context("with credentials", func() {
+		It("should authenticate with mysql_native_password and run SELECT 1", func() {
+			gotQuery := make(chan string, 1)
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				defer GinkgoRecover()
+				b.handshake("10.11.6-MariaDB")
+				user, auth, database := parseHandshakeResponse(b.receive())
+				Expect(user).To(Equal("app"))
+				Expect(database).To(Equal("inventory"))
+				Expect(checkNativePassword("s3cret", auth)).To(BeTrue())
+				b.ok()
+
+				query := b.receive()
+				Expect(query[0]).To(Equal(byte(0x03)))
+				gotQuery <- string(query[1:])
+				b.send([]byte{1})                          // column count
+				b.send([]byte("\x03def\x00\x00\x00\x011")) // column definition (abridged)
+				b.send([]byte{0xfe, 0, 0, 2, 0})           // EOF
+				b.send([]byte{1, '1'})                     // row
+				b.send([]byte{0xfe, 0, 0, 2, 0})           // EOF
+			})
+
+			spec := corev1alpha1.MySQLProbe{Database: "inventory", CredentialsSecretRef: credsRef}
+			detail, err := MySQL(probeCtx(), addr, spec, secrets)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(detail).To(Equal("server version 10.11.6-MariaDB"))
+			Expect(<-gotQuery).To(Equal("SELECT 1"))
+		})
+
+		It("should report AuthenticationFailed with the server's error packet", func() {
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				b.handshake("8.0.36")
+				b.receive()
+				b.fail(1045, "28000", "Access denied for user 'app'@'10.0.0.7' (using password: YES)")
+			})
+			_, err := MySQL(probeCtx(), addr, corev1alpha1.MySQLProbe{CredentialsSecretRef: credsRef}, secrets)
+			Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+			Expect(err.Error()).To(HavePrefix("ERROR 1045 (28000): Access denied"))
+		})
+
+		It("should report QueryFailed when SELECT 1 returns an error", func() {
+			addr := startFakeMySQL(func(b *mysqlBackend) {
+				b.handshake("8.0.36")
+				b.receive()
+				b.ok()
+				b.receive()
+				b.fail(1046, "3D000", "No database selected")
+			})
+			_, err := MySQL(probeCtx(), addr, corev1alpha1.MySQLProbe{CredentialsSecretRef: credsRef}, secrets)
+			Expect(Reason(err)).To(Equal(ReasonQueryFailed))
+		})
+	})
+})
+
+var _ = Describe("mysqlScramble", func() {
+	It("should return an empty auth response for an empty password", func() {
+		Expect(mysqlScramble(mysqlNativePassword, "", mysqlTestNonce)).To(BeEmpty())
+	})
+
+	It("should produce a 32-byte caching_sha2_password scramble", func() {
+		Expect(mysqlScramble(mysqlCachingSHA2, "s3cret", mysqlTestNonce)).To(HaveLen(32))
+	})
+
+	It("should reject unsupported plugins", func() {
+		_, err := mysqlScramble("sha256_password", "s3cret", mysqlTestNonce)
+		Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+	})
+})
+
+var _ = Describe("mysqlEncryptPassword", func() {
+	It("should report ProtocolError instead of panicking on an empty nonce", func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).NotTo(HaveOccurred())
+		der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+		Expect(err).NotTo(HaveOccurred())
+		pemKey := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+		_, err = mysqlEncryptPassword("s3cret", nil, pemKey)
+		Expect(Reason(err)).To(Equal(ReasonProtocolError))
+
+		enc, err := mysqlEncryptPassword("s3cret", mysqlTestNonce, pemKey)
+		Expect(err).NotTo(HaveOccurred())
+		plain, err := rsa.DecryptOAEP(sha1.New(), nil, key, enc, nil) //nolint:gosec
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plain).To(HaveLen(len("s3cret") + 1))
+	})
+
+	It("should report AuthenticationFailed for an invalid public key", func() {
+		_, err := mysqlEncryptPassword("s3cret", mysqlTestNonce, []byte("not a key"))
+		Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+	})
+})
@@ -0,0 +1,506 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // required by the PostgreSQL MD5 authentication method
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the database probes.
+const (
+	ReasonDatabaseInRecovery   = "DatabaseInRecovery"
+	ReasonDatabaseStartingUp   = "DatabaseStartingUp"
+	ReasonDatabaseShuttingDown = "DatabaseShuttingDown"
+	ReasonTooManyConnections   = "TooManyConnections"
+	ReasonAuthenticationFailed = "AuthenticationFailed"
+	ReasonQueryFailed          = "QueryFailed"
+	ReasonTLSUnavailable       = "TLSUnavailable"
+	ReasonProtocolError        = "ProtocolError"
+	ReasonServerError          = "ServerError"
+)
+
+const (
+	pgProtocolVersion = 196608   // 3.0
+	pgSSLRequestCode  = 80877103 // 1234 << 16 | 5679
+	pgProbeUser       = "bootchain-probe"
+	pgDefaultDatabase = "postgres"
+	pgDefaultQuery    = "SELECT 1"
+	pgMaxMessageSize  = 1 << 20
+	// scramMaxIterations bounds the server-chosen PBKDF2 work, which cannot be
+	// interrupted by the probe's context.
+	scramMaxIterations = 1 << 20
+)
+
+// Postgres performs the PostgreSQL startup handshake against addr.
+// Without credentials the server is ready as soon as it asks the client to
+// authenticate (or rejects the probe's user), mirroring pg_isready. With
+// credentials the probe authenticates and runs spec.Query as well.
+func Postgres(ctx context.Context, addr string, spec corev1alpha1.PostgresProbe, secrets SecretReader) error {
+	var creds *authCredentials
+	if spec.CredentialsSecretRef != nil {
+		c, err := readCredentials(ctx, secrets, spec.CredentialsSecretRef)
+		if err != nil {
+			return err
+		}
+		creds = &c
+	}
+
+	var d net.Dialer
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = raw.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = raw.SetDeadline(deadline)
+	}
+
+	conn, err := pgNegotiateTLS(raw, addr, spec.SSLMode)
+	if err != nil {
+		return err
+	}
+
+	user := pgProbeUser
+	if creds != nil {
+		user = creds.username
+	}
+	database := spec.Database
+	if database == "" {
+		database = pgDefaultDatabase
+	}
+
+	pc := &pgConn{conn: conn, r: bufio.NewReader(conn)}
+	if err := pc.startup(user, database); err != nil {
+		return err
+	}
+	if err := pc.authenticate(user, creds); err != nil {
+		return err
+	}
+	if creds == nil {
+		// The server is accepting connections; that is all pg_isready checks.
+		return nil
+	}
+	if err := pc.waitReady(); err != nil {
+		return err
+	}
+
+	query := spec.Query
+	if query == "" {
+		query = pgDefaultQuery
+	}
+	if err := pc.query(query); err != nil {
+		return err
+	}
+	_ = pc.send('X', nil)
+	return nil
+}
+
+// pgNegotiateTLS sends an SSLRequest unless sslMode is "disable" and upgrades the
+// connection when the server accepts it.
+func pgNegotiateTLS(conn net.Conn, addr, sslMode string) (net.Conn, error) {
+	if sslMode == "disable" {
+		return conn, nil
+	}
+
+	req := make([]byte, 8)
+	binary.BigEndian.PutUint32(req[0:4], 8)
+	binary.BigEndian.PutUint32(req[4:8], pgSSLRequestCode)
+	if _, err := conn.Write(req); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, 1)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return nil, err
+	}
+
+	switch resp[0] {
+	case 'S':
+		host, _, _ := net.SplitHostPort(addr)
+		// Like libpq's sslmode=prefer/require, the certificate is not verified.
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host, InsecureSkipVerify: true}) //nolint:gosec
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, err
+		}
+		return tlsConn, nil
+	case 'N':
+		if sslMode == "require" {
+			return nil, errorf(ReasonTLSUnavailable, "server does not support SSL but sslMode is require")
+		}
+		return conn, nil
+	default:
+		return nil, errorf(ReasonProtocolError, "unexpected response %q to SSLRequest", resp[0])
+	}
+}
+
+// pgConn is a minimal PostgreSQL frontend connection.
+type pgConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// send writes a typed frontend message.
+func (c *pgConn) send(typ byte, payload []byte) error {
+	msg := make([]byte, 5+len(payload))
+	msg[0] = typ
+	binary.BigEndian.PutUint32(msg[1:5], uint32(4+len(payload)))
+	copy(msg[5:], payload)
+	_, err := c.conn.Write(msg)
+	return err
+}
+
+// receive reads one typed backend message.
+func (c *pgConn) receive() (byte, []byte, error) {
+	hdr := make([]byte, 5)
+	if _, err := io.ReadFull(c.r, hdr); err != nil {
+		return 0, nil, err
+	}
+	n := int(binary.BigEndian.Uint32(hdr[1:5])) - 4
+	if n < 0 || n > pgMaxMessageSize {
+		return 0, nil, errorf(ReasonProtocolError, "invalid message length %d", n)
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(c.r, payload); err != nil {
+		return 0, nil, err
+	}
+	return hdr[0], payload, nil
+}
+
+// startup sends the StartupMessage for user and database.
+func (c *pgConn) startup(user, database string) error {
+	var b []byte
+	b = binary.BigEndian.AppendUint32(b, 0) // length, filled in below
+	b = binary.BigEndian.AppendUint32(b, pgProtocolVersion)
+	for _, kv := range [][2]string{{"user", user}, {"database", database}, {"application_name", pgProbeUser}} {
+		b = append(b, kv[0]...)
+		b = append(b, 0)
+		b = append(b, kv[1]...)
+		b = append(b, 0)
+	}
+	b = append(b, 0)
+	binary.BigEndian.PutUint32(b[0:4], uint32(len(b)))
+	_, err := c.conn.Write(b)
+	return err
+}
+
+// authenticate drives the authentication exchange. Without credentials it returns
+// nil as soon as the server requests authentication or rejects the probe's user,
+// since either answer proves the server accepts connections.
+func (c *pgConn) authenticate(user string, creds *authCredentials) error {
+	var scram *scramClient
+	for {
+		typ, payload, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'E':
+			pgErr := parsePgError(payload)
+			if creds == nil && pgErr.acceptsConnections() {
+				return nil
+			}
+			return pgErr.probeError()
+		case 'N':
+			continue
+		case 'R':
+		default:
+			return errorf(ReasonProtocolError, "unexpected message %q during authentication", typ)
+		}
+
+		if len(payload) < 4 {
+			return errorf(ReasonProtocolError, "short authentication message")
+		}
+		code := binary.BigEndian.Uint32(payload[0:4])
+		if code == 0 {
+			return nil // AuthenticationOk
+		}
+		if creds == nil {
+			return nil
+		}
+
+		switch code {
+		case 3: // AuthenticationCleartextPassword
+			err = c.send('p', append([]byte(creds.password), 0))
+		case 5: // AuthenticationMD5Password
+			if len(payload) < 8 {
+				return errorf(ReasonProtocolError, "short MD5 salt")
+			}
+			err = c.send('p', append([]byte(pgMD5Password(user, creds.password, payload[4:8])), 0))
+		case 10: // AuthenticationSASL
+			if !strings.Contains(string(payload[4:]), "SCRAM-SHA-256\x00") {
+				return errorf(ReasonAuthenticationFailed, "server offers no supported SASL mechanism")
+			}
+			scram, err = newSCRAMClient("", creds.password)
+			if err != nil {
+				return err
+			}
+			first := scram.clientFirst()
+			var msg []byte
+			msg = append(msg, "SCRAM-SHA-256"...)
+			msg = append(msg, 0)
+			msg = binary.BigEndian.AppendUint32(msg, uint32(len(first)))
+			msg = append(msg, first...)
+			err = c.send('p', msg)
+		case 11: // AuthenticationSASLContinue
+			if scram == nil {
+				return errorf(ReasonProtocolError, "SASLContinue without SASL")
+			}
+			var final string
+			if final, err = scram.clientFinal(string(payload[4:])); err != nil {
+				return err
+			}
+			err = c.send('p', []byte(final))
+		case 12: // AuthenticationSASLFinal
+			if scram == nil {
+				return errorf(ReasonProtocolError, "SASLFinal without SASL")
+			}
+			if err := scram.verifyServerFinal(string(payload[4:])); err != nil {
+				return errorf(ReasonAuthenticationFailed, "%v", err)
+			}
+		default:
+			return errorf(ReasonAuthenticationFailed, "unsupported authentication method %d", code)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// waitReady consumes messages until ReadyForQuery.
+func (c *pgConn) waitReady() error {
+	for {
+		typ, payload, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'Z':
+			return nil
+		case 'E':
+			return parsePgError(payload).probeError()
+		}
+	}
+}
+
+// query runs a simple query and consumes its results up to ReadyForQuery.
+func (c *pgConn) query(q string) error {
+	if err := c.send('Q', append([]byte(q), 0)); err != nil {
+		return err
+	}
+	var queryErr error
+	for {
+		typ, payload, err := c.receive()
+		if err != nil {
+			return err
+		}
+		switch typ {
+		case 'E':
+			pgErr := parsePgError(payload)
+			queryErr = pgErr.probeError()
+			if Reason(queryErr) == ReasonServerError {
+				queryErr = errorf(ReasonQueryFailed, "query failed: %s", pgErr)
+			}
+		case 'Z':
+			return queryErr
+		}
+	}
+}
+
+// pgError is a decoded ErrorResponse.
+type pgError struct {
+	severity string
+	code     string
+	message  string
+}
+
+func (e pgError) String() string {
+	return fmt.Sprintf("%s: %s (SQLSTATE %s)", e.severity, e.message, e.code)
+}
+
+// parsePgError decodes the fields of an ErrorResponse payload.
+func parsePgError(payload []byte) pgError {
+	var e pgError
+	for len(payload) > 0 && payload[0] != 0 {
+		field := payload[0]
+		end := 1
+		for end < len(payload) && payload[end] != 0 {
+			end++
+		}
+		value := string(payload[1:end])
+		switch field {
+		case 'S':
+			e.severity = value
+		case 'C':
+			e.code = value
+		case 'M':
+			e.message = value
+		}
+		if end >= len(payload) {
+			break
+		}
+		payload = payload[end+1:]
+	}
+	return e
+}
+
+// acceptsConnections reports whether the error proves that the server is accepting
+// connections, e.g. because it rejected the probe's user or database.
+func (e pgError) acceptsConnections() bool {
+	return strings.HasPrefix(e.code, "28") || e.code == "3D000"
+}
+
+// probeError maps the SQLSTATE to a probe reason.
+func (e pgError) probeError() error {
+	switch {
+	case e.code == "57P03" && strings.Contains(e.message, "starting up"):
+		return errorf(ReasonDatabaseStartingUp, "%s", e)
+	case e.code == "57P03" && strings.Contains(e.message, "shutting down"):
+		return errorf(ReasonDatabaseShuttingDown, "%s", e)
+	case e.code == "57P03":
+		// "in recovery mode" and "not yet accepting connections" (hot standby catching up).
+		return errorf(ReasonDatabaseInRecovery, "%s", e)
+	case e.code == "53300":
+		return errorf(ReasonTooManyConnections, "%s", e)
+	case strings.HasPrefix(e.code, "28"):
+		return errorf(ReasonAuthenticationFailed, "%s", e)
+	default:
+		return errorf(ReasonServerError, "%s", e)
+	}
+}
+
+// pgMD5Password computes "md5" + md5(md5(password + user) + salt).
+func pgMD5Password(user, password string, salt []byte) string {
+	inner := md5.Sum([]byte(password + user))                               //nolint:gosec
+	outer := md5.Sum(append([]byte(hex.EncodeToString(inner[:])), salt...)) //nolint:gosec
+	return "md5" + hex.EncodeToString(outer[:])
+}
+
+// scramClient implements the client side of SCRAM-SHA-256 (RFC 5802, RFC 7677)
+// without channel binding.
+type scramClient struct {
+	username        string
+	password        string
+	clientNonce     string
+	clientFirstBare string
+	authMessage     string
+	saltedPassword  []byte
+}
+
+func newSCRAMClient(username, password string) (*scramClient, error) {
+	nonce := make([]byte, 18)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return &scramClient{
+		username:    username,
+		password:    password,
+		clientNonce: base64.StdEncoding.EncodeToString(nonce),
+	}, nil
+}
+
+// clientFirst returns the client-first-message.
+func (s *scramClient) clientFirst() string {
+	s.clientFirstBare = "n=" + s.username + ",r=" + s.clientNonce
+	return "n,," + s.clientFirstBare
+}
+
+// clientFinal processes the server-first-message and returns the client-final-message.
+// Iteration counts above scramMaxIterations are rejected as a ProtocolError.
+func (s *scramClient) clientFinal(serverFirst string) (string, error) {
+	var nonce, salt string
+	iterations := 0
+	for _, attr := range strings.Split(serverFirst, ",") {
+		if len(attr) < 2 || attr[1] != '=' {
+			continue
+		}
+		switch attr[0] {
+		case 'r':
+			nonce = attr[2:]
+		case 's':
+			salt = attr[2:]
+		case 'i':
+			iterations, _ = strconv.Atoi(attr[2:])
+		}
+	}
+	if !strings.HasPrefix(nonce, s.clientNonce) || salt == "" || iterations <= 0 {
+		return "", errorf(ReasonAuthenticationFailed, "invalid SCRAM server-first-message")
+	}
+	if iterations > scramMaxIterations {
+		return "", errorf(ReasonProtocolError, "SCRAM iteration count %d exceeds %d", iterations, scramMaxIterations)
+	}
+	saltBytes, err := base64.StdEncoding.DecodeString(salt)
+	if err != nil {
+		return "", errorf(ReasonAuthenticationFailed, "invalid SCRAM salt: %v", err)
+	}
+	s.saltedPassword, err = pbkdf2.Key(sha256.New, s.password, saltBytes, iterations, sha256.Size)
+	if err != nil {
+		return "", errorf(ReasonAuthenticationFailed, "%v", err)
+	}
+
+	withoutProof := "c=biws,r=" + nonce
+	s.authMessage = s.clientFirstBare + "," + serverFirst + "," + withoutProof
+
+	clientKey := scramHMAC(s.saltedPassword, "Client Key")
+	storedKey := sha256.Sum256(clientKey)
+	signature := scramHMAC(storedKey[:], s.authMessage)
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ signature[i]
+	}
+	return withoutProof + ",p=" + base64.StdEncoding.EncodeToString(proof), nil
+}
+
+// verifyServerFinal checks the server signature in the server-final-message.
+func (s *scramClient) verifyServerFinal(serverFinal string) error {
+	if strings.HasPrefix(serverFinal, "e=") {
+		return fmt.Errorf("SCRAM authentication failed: %s", serverFinal[2:])
+	}
+	if !strings.HasPrefix(serverFinal, "v=") {
+		return fmt.Errorf("invalid SCRAM server-final-message")
+	}
+	got, err := base64.StdEncoding.DecodeString(serverFinal[2:])
+	if err != nil {
+		return fmt.Errorf("invalid SCRAM server signature: %w", err)
+	}
+	serverKey := scramHMAC(s.saltedPassword, "Server Key")
+	if !hmac.Equal(got, scramHMAC(serverKey, s.authMessage)) {
+		return fmt.Errorf("SCRAM server signature mismatch")
+	}
+	return nil
+}
+
+func scramHMAC(key []byte, msg string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(msg))
+	return h.Sum(nil)
+}
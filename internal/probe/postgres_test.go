@@ -0,0 +1,275 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// mapSecrets is a SecretReader backed by a map keyed by "name/key". ConfigMap keys
+// are stored under "configmap/name/key".
+type mapSecrets map[string]string
+
+func (m mapSecrets) ReadSecretKey(_ context.Context, name, key string) (string, error) {
+	v, ok := m[name+"/"+key]
+	if !ok {
+		return "", fmt.Errorf("secret %s has no key %s", name, key)
+	}
+	return v, nil
+}
+
+func (m mapSecrets) ReadConfigMapKey(_ context.Context, name, key string) (string, error) {
+	v, ok := m["configmap/"+name+"/"+key]
+	if !ok {
+		return "", fmt.Errorf("configmap %s has no key %s", name, key)
+	}
+	return v, nil
+}
+
+// pgBackend is the server side of a fake PostgreSQL connection.
+type pgBackend struct {
+	conn   net.Conn
+	params map[string]string
+}
+
+func (b *pgBackend) send(typ byte, payload []byte) {
+	msg := []byte{typ}
+	msg = binary.BigEndian.AppendUint32(msg, uint32(4+len(payload)))
+	_, _ = b.conn.Write(append(msg, payload...))
+}
+
+func (b *pgBackend) receive() (byte, []byte) {
+	hdr := make([]byte, 5)
+	if _, err := io.ReadFull(b.conn, hdr); err != nil {
+		return 0, nil
+	}
+	payload := make([]byte, binary.BigEndian.Uint32(hdr[1:5])-4)
+	_, _ = io.ReadFull(b.conn, payload)
+	return hdr[0], payload
+}
+
+func (b *pgBackend) auth(code uint32, extra ...byte) {
+	b.send('R', append(binary.BigEndian.AppendUint32(nil, code), extra...))
+}
+
+func (b *pgBackend) fail(code, message string) {
+	var p []byte
+	for _, f := range []struct {
+		t byte
+		v string
+	}{{'S', "FATAL"}, {'C', code}, {'M', message}} {
+		p = append(p, f.t)
+		p = append(p, f.v...)
+		p = append(p, 0)
+	}
+	b.send('E', append(p, 0))
+}
+
+func (b *pgBackend) ready() {
+	b.send('S', []byte("server_version\x0016.2\x00"))
+	b.send('Z', []byte{'I'})
+}
+
+// startFakePostgres accepts one connection, answers the SSLRequest with sslAnswer,
+// reads the startup message and hands the connection to handle.
+func startFakePostgres(sslAnswer byte, handle func(b *pgBackend)) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	go func() {
+		defer GinkgoRecover()
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for {
+			hdr := make([]byte, 8)
+			if _, err := io.ReadFull(conn, hdr); err != nil {
+				return
+			}
+			rest := make([]byte, binary.BigEndian.Uint32(hdr[0:4])-8)
+			if _, err := io.ReadFull(conn, rest); err != nil {
+				return
+			}
+			if binary.BigEndian.Uint32(hdr[4:8]) == pgSSLRequestCode {
+				_, _ = conn.Write([]byte{sslAnswer})
+				continue
+			}
+			params := map[string]string{}
+			fields := bytes.Split(bytes.TrimRight(rest, "\x00"), []byte{0})
+			for i := 0; i+1 < len(fields); i += 2 {
+				params[string(fields[i])] = string(fields[i+1])
+			}
+			handle(&pgBackend{conn: conn, params: params})
+			return
+		}
+	}()
+	return lis.Addr().String()
+}
+
+var _ = Describe("Postgres", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	credsRef := &corev1alpha1.SecretCredentialsRef{Name: "pg"}
+	secrets := mapSecrets{"pg/username": "app", "pg/password": "s3cret"}
+
+	Context("without credentials", func() {
+		It("should be ready once the server asks for authentication", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.auth(5, 1, 2, 3, 4) // AuthenticationMD5Password
+			})
+			Expect(Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{}, nil)).To(Succeed())
+		})
+
+		It("should be ready when the server rejects the probe's user", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.fail("28000", `no pg_hba.conf entry for user "bootchain-probe"`)
+			})
+			Expect(Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{}, nil)).To(Succeed())
+		})
+
+		It("should report DatabaseInRecovery while the server is recovering", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.fail("57P03", "the database system is in recovery mode")
+			})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(Reason(err)).To(Equal(ReasonDatabaseInRecovery))
+		})
+
+		It("should report DatabaseStartingUp while the server is starting", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.fail("57P03", "the database system is starting up")
+			})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{}, nil)
+			Expect(Reason(err)).To(Equal(ReasonDatabaseStartingUp))
+		})
+
+		It("should report TooManyConnections when the server is full", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.fail("53300", "sorry, too many clients already")
+			})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{}, nil)
+			Expect(err).To(HaveOccurred())
+			Expect(Reason(err)).To(Equal(ReasonTooManyConnections))
+		})
+
+		It("should fail with TLSUnavailable when sslMode is require and the server refuses SSL", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{SSLMode: "require"}, nil)
+			Expect(Reason(err)).To(Equal(ReasonTLSUnavailable))
+		})
+	})
+
+	Context("with credentials", func() {
+		It("should authenticate with the Secret's credentials and run the query", func() {
+			gotQuery := make(chan string, 1)
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				defer GinkgoRecover()
+				Expect(b.params["user"]).To(Equal("app"))
+				Expect(b.params["database"]).To(Equal("orders"))
+				b.auth(3) // AuthenticationCleartextPassword
+				typ, payload := b.receive()
+				Expect(typ).To(Equal(byte('p')))
+				Expect(string(payload)).To(Equal("s3cret\x00"))
+				b.auth(0)
+				b.ready()
+				typ, payload = b.receive()
+				Expect(typ).To(Equal(byte('Q')))
+				gotQuery <- string(bytes.TrimRight(payload, "\x00"))
+				b.send('C', []byte("SELECT 1\x00"))
+				b.send('Z', []byte{'I'})
+			})
+
+			spec := corev1alpha1.PostgresProbe{Database: "orders", CredentialsSecretRef: credsRef}
+			Expect(Postgres(probeCtx(), addr, spec, secrets)).To(Succeed())
+			Expect(<-gotQuery).To(Equal("SELECT 1"))
+		})
+
+		It("should report AuthenticationFailed when the password is rejected", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.auth(3)
+				b.receive()
+				b.fail("28P01", `password authentication failed for user "app"`)
+			})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{CredentialsSecretRef: credsRef}, secrets)
+			Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+		})
+
+		It("should report QueryFailed when the query returns an error", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.auth(0)
+				b.ready()
+				b.receive()
+				b.fail("42P01", `relation "missing" does not exist`)
+				b.send('Z', []byte{'I'})
+			})
+			spec := corev1alpha1.PostgresProbe{CredentialsSecretRef: credsRef, Query: "SELECT * FROM missing"}
+			err := Postgres(probeCtx(), addr, spec, secrets)
+			Expect(Reason(err)).To(Equal(ReasonQueryFailed))
+		})
+
+		It("should report ProtocolError when the server asks for too many SCRAM iterations", func() {
+			addr := startFakePostgres('N', func(b *pgBackend) {
+				b.auth(10, []byte("SCRAM-SHA-256\x00\x00")...) // AuthenticationSASL
+				_, payload := b.receive()
+				_, first, _ := strings.Cut(string(payload), "r=")
+				b.auth(11, []byte("r="+first+"server,s=c2FsdA==,i=2000000000")...) // AuthenticationSASLContinue
+				b.receive()
+			})
+			err := Postgres(probeCtx(), addr, corev1alpha1.PostgresProbe{CredentialsSecretRef: credsRef}, secrets)
+			Expect(Reason(err)).To(Equal(ReasonProtocolError))
+		})
+
+		It("should report SecretNotFound when the Secret key cannot be read", func() {
+			spec := corev1alpha1.PostgresProbe{CredentialsSecretRef: &corev1alpha1.SecretCredentialsRef{Name: "missing"}}
+			err := Postgres(probeCtx(), "127.0.0.1:1", spec, secrets)
+			Expect(Reason(err)).To(Equal(ReasonSecretNotFound))
+		})
+	})
+})
+
+var _ = Describe("scramClient", func() {
+	It("should produce the RFC 7677 SCRAM-SHA-256 test vector", func() {
+		s := &scramClient{username: "user", password: "pencil", clientNonce: "rOprNGfwEbeRWgbNEkqO"}
+		Expect(s.clientFirst()).To(Equal("n,,n=user,r=rOprNGfwEbeRWgbNEkqO"))
+
+		final, err := s.clientFinal("r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(final).To(Equal("c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="))
+		Expect(s.verifyServerFinal("v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4=")).To(Succeed())
+	})
+})
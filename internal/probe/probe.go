@@ -0,0 +1,301 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package probe implements the readiness checks shared by the BootDependency
+// controller and the bootchain-probe binary that runs inside injected init containers.
+// Keeping a single implementation guarantees that both sides agree on when a
+// dependency is ready.
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+const (
+	// ReasonUnreachable is reported for failures that carry no more specific reason,
+	// such as a refused connection or a timeout.
+	ReasonUnreachable = "Unreachable"
+	// ReasonSecretNotFound is reported when a Secret referenced by a probe cannot be read.
+	ReasonSecretNotFound = "SecretNotFound"
+	// ReasonConfigMapNotFound is reported when a ConfigMap referenced by a probe cannot be read.
+	ReasonConfigMapNotFound = "ConfigMapNotFound"
+	// ReasonUnexpectedStatus is reported when an HTTP endpoint answers with a status
+	// code that is not accepted.
+	ReasonUnexpectedStatus = "UnexpectedStatus"
+)
+
+// Error is a probe failure with a machine-readable reason suitable for
+// DependencyStatus.Reason. Terminal failures, such as a failed Job, will not
+// resolve by probing again.
+type Error struct {
+	Reason   string
+	Message  string
+	Terminal bool
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// errorf returns an *Error with the given reason and formatted message.
+func errorf(reason, format string, args ...any) error {
+	return &Error{Reason: reason, Message: fmt.Sprintf(format, args...)}
+}
+
+// Reason returns the reason carried by err, or ReasonUnreachable when err is not an *Error.
+func Reason(err error) string {
+	var pe *Error
+	if errors.As(err, &pe) {
+		return pe.Reason
+	}
+	return ReasonUnreachable
+}
+
+// IsTerminal reports whether err is a terminal probe failure.
+func IsTerminal(err error) bool {
+	var pe *Error
+	return errors.As(err, &pe) && pe.Terminal
+}
+
+// Run probes a single dependency on host and returns nil once it is ready.
+// On success it also returns what the probe learned about the dependency, such as
+// a server version, or an empty string when there is nothing to report.
+// The caller controls the overall deadline through ctx. Secrets referenced by the
+// dependency are resolved through secrets, and Kubernetes objects, including the Service
+// of a named port, are read through objects.
+func Run(ctx context.Context, host string, dep corev1alpha1.ServiceDependency, secrets SecretReader, objects ObjectReader) (string, error) {
+	if dep.WorkloadRef != nil {
+		return Workload(ctx, objects, *dep.WorkloadRef)
+	}
+	if dep.JobRef != nil {
+		return Job(ctx, objects, *dep.JobRef)
+	}
+	if dep.ResourceRef != nil {
+		return Resource(ctx, objects, *dep.ResourceRef)
+	}
+	if dep.Endpoints != nil {
+		return Endpoints(ctx, objects, dep.Namespace, dep.Service, *dep.Endpoints)
+	}
+	if dep.PortName != "" && dep.Port == 0 {
+		port, err := ServicePort(ctx, objects, dep.Namespace, dep.Service, dep.PortName)
+		if err != nil {
+			return "", err
+		}
+		dep.Port = port
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(dep.Port)))
+	switch {
+	case dep.GRPC != nil:
+		return "", GRPC(ctx, addr, *dep.GRPC)
+	case dep.Postgres != nil:
+		return "", Postgres(ctx, addr, *dep.Postgres, secrets)
+	case dep.MySQL != nil:
+		return MySQL(ctx, addr, *dep.MySQL, secrets)
+	case dep.Redis != nil:
+		return Redis(ctx, addr, *dep.Redis, secrets)
+	case dep.Kafka != nil:
+		return Kafka(ctx, addr, *dep.Kafka)
+	case dep.AMQP != nil:
+		return AMQP(ctx, addr, *dep.AMQP, secrets)
+	case dep.MongoDB != nil:
+		return MongoDB(ctx, addr, *dep.MongoDB)
+	case dep.DNS != nil:
+		return DNS(ctx, host, *dep.DNS)
+	case dep.TLS != nil:
+		return TLS(ctx, addr, host, *dep.TLS, secrets)
+	case dep.HTTPPath != "":
+		return "", eachFamily(dep, func(network string) error {
+			return HTTP(ctx, network, addr, dep, secrets)
+		})
+	default:
+		proxy, err := proxyURL(ctx, dep, secrets)
+		if err != nil {
+			return "", err
+		}
+		return "", eachFamily(dep, func(network string) error {
+			return TCP(ctx, network, addr, proxy)
+		})
+	}
+}
+
+// eachFamily runs check once over "tcp", or over "tcp4" and then "tcp6" for a
+// dualStack dependency, in which case a failure names the address family.
+func eachFamily(dep corev1alpha1.ServiceDependency, check func(network string) error) error {
+	if !dep.DualStack {
+		return check("tcp")
+	}
+	for _, family := range []struct{ network, name string }{{"tcp4", "IPv4"}, {"tcp6", "IPv6"}} {
+		if err := check(family.network); err != nil {
+			return fmt.Errorf("%s: %w", family.name, err)
+		}
+	}
+	return nil
+}
+
+// MarshalDependency encodes a dependency for the DependencyEnv variable.
+func MarshalDependency(dep corev1alpha1.ServiceDependency) string {
+	// ServiceDependency only contains plain data fields, so encoding cannot fail.
+	b, _ := json.Marshal(dep)
+	return string(b)
+}
+
+// UnmarshalDependency decodes a dependency previously encoded with MarshalDependency.
+func UnmarshalDependency(s string) (corev1alpha1.ServiceDependency, error) {
+	var dep corev1alpha1.ServiceDependency
+	if err := json.Unmarshal([]byte(s), &dep); err != nil {
+		return dep, fmt.Errorf("failed to decode dependency: %w", err)
+	}
+	return dep, nil
+}
+
+// TCP succeeds when a connection to addr over network ("tcp", "tcp4" or "tcp6") can be
+// established. When proxy is set, the connection is tunnelled through that HTTP proxy
+// with CONNECT.
+func TCP(ctx context.Context, network, addr, proxy string) error {
+	var conn net.Conn
+	var err error
+	if proxy != "" {
+		conn, err = dialProxy(ctx, proxy, addr)
+	} else {
+		var d net.Dialer
+		conn, err = d.DialContext(ctx, network, addr)
+	}
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// HTTP performs the request described by the dependency's http* fields against addr
+// over network ("tcp", "tcp4" or "tcp6") and succeeds when the response status is
+// accepted and, when httpExpression is set, the expression evaluates to true. Header
+// values, the CA bundle and the client certificate are read through secrets. The
+// request goes through the dependency's proxy, if any.
+func HTTP(ctx context.Context, network, addr string, dep corev1alpha1.ServiceDependency, secrets SecretReader) error {
+	scheme := dep.HTTPScheme
+	if scheme == "" {
+		scheme = "http"
+	}
+	url := fmt.Sprintf("%s://%s%s", scheme, addr, dep.HTTPPath)
+
+	method := dep.HTTPMethod
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	for _, h := range dep.HTTPHeaders {
+		value, err := headerValue(ctx, h, secrets)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(h.Name, value)
+	}
+	tlsConfig, err := httpTLSConfig(ctx, dep, secrets)
+	if err != nil {
+		return err
+	}
+
+	// Keep-alives are disabled so that the per-probe transport does not leave
+	// idle connections (and their goroutines) behind.
+	transport := &http.Transport{
+		DisableKeepAlives: true,
+		TLSClientConfig:   tlsConfig,
+		DialContext: func(ctx context.Context, _, addr string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, addr)
+		},
+	}
+	proxy, err := proxyURL(ctx, dep, secrets)
+	if err != nil {
+		return err
+	}
+	if proxy != "" {
+		u, err := parseProxyURL(proxy)
+		if err != nil {
+			return err
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	httpClient := &http.Client{Transport: transport}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if !StatusAccepted(resp.StatusCode, dep.HTTPExpectedStatuses) {
+		return errorf(ReasonUnexpectedStatus, "HTTP %d", resp.StatusCode)
+	}
+	if dep.HTTPExpression != "" {
+		return evalHTTPExpression(dep.HTTPExpression, resp)
+	}
+	return nil
+}
+
+// httpTLSConfig returns the TLS configuration of an HTTPS probe: the server certificate
+// is verified against caBundleRef instead of the system roots when it is set, and the
+// certificate from clientCertSecretRef is presented for mutual TLS.
+func httpTLSConfig(ctx context.Context, dep corev1alpha1.ServiceDependency, secrets SecretReader) (*tls.Config, error) {
+	cfg := &tls.Config{InsecureSkipVerify: dep.Insecure} //nolint:gosec
+	if ref := dep.CABundleRef; ref != nil {
+		bundle, err := caBundle(ctx, ref, secrets)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM([]byte(bundle)) {
+			return nil, errorf(ReasonCertificateInvalid, "caBundleRef holds no PEM certificates")
+		}
+	}
+	if ref := dep.ClientCertSecretRef; ref != nil {
+		cert, err := secrets.ReadSecretKey(ctx, ref.Name, clientCertKey(ref))
+		if err != nil {
+			return nil, errorf(ReasonSecretNotFound, "client certificate: %v", err)
+		}
+		key, err := secrets.ReadSecretKey(ctx, ref.Name, clientKeyKey(ref))
+		if err != nil {
+			return nil, errorf(ReasonSecretNotFound, "client key: %v", err)
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, errorf(ReasonCertificateInvalid, "client certificate in secret %s: %v", ref.Name, err)
+		}
+		cfg.Certificates = []tls.Certificate{pair}
+	}
+	return cfg, nil
+}
+
+// StatusAccepted returns true when code is in the accepted list.
+// When the list is empty it falls back to the 2xx range (200–299).
+func StatusAccepted(code int, accepted []int32) bool {
+	if len(accepted) == 0 {
+		return code >= 200 && code < 300
+	}
+	return slices.Contains(accepted, int32(code))
+}
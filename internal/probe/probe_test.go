@@ -0,0 +1,108 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("Run", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	// listen accepts connections on address and returns its port.
+	listen := func(address string) int32 {
+		lis, err := net.Listen("tcp", address)
+		if err != nil {
+			Skip("cannot listen on " + address + ": " + err.Error())
+		}
+		DeferCleanup(lis.Close)
+		go func() {
+			for {
+				conn, err := lis.Accept()
+				if err != nil {
+					return
+				}
+				_ = conn.Close()
+			}
+		}()
+		return int32(lis.Addr().(*net.TCPAddr).Port)
+	}
+
+	It("should probe IPv6 literals over TCP and HTTP", func() {
+		port := listen("[::1]:0")
+		dep := corev1alpha1.ServiceDependency{Host: "::1", Port: port}
+		_, err := Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).NotTo(HaveOccurred())
+
+		lis, err := net.Listen("tcp", "[::1]:0")
+		Expect(err).NotTo(HaveOccurred())
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+		srv.Listener = lis
+		srv.Start()
+		DeferCleanup(srv.Close)
+		dep = corev1alpha1.ServiceDependency{Host: "::1", Port: int32(lis.Addr().(*net.TCPAddr).Port), HTTPPath: "/healthz"}
+		_, err = Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should require a dualStack dependency to be reachable over IPv4 and IPv6", func() {
+		port := listen("127.0.0.1:0")
+		dep := corev1alpha1.ServiceDependency{Host: "127.0.0.1", Port: port}
+		_, err := Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).NotTo(HaveOccurred())
+
+		dep.DualStack = true
+		_, err = Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).To(MatchError(HavePrefix("IPv6: ")))
+		Expect(Reason(err)).To(Equal(ReasonUnreachable))
+
+		port = listen("[::1]:0")
+		dep = corev1alpha1.ServiceDependency{Host: "::1", Port: port, DualStack: true}
+		_, err = Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).To(MatchError(HavePrefix("IPv4: ")))
+	})
+
+	It("should succeed for a dualStack host with A and AAAA addresses that accept connections", func() {
+		addrs, _ := net.DefaultResolver.LookupIPAddr(probeCtx(), "localhost")
+		var v4, v6 bool
+		for _, a := range addrs {
+			v4 = v4 || a.IP.To4() != nil
+			v6 = v6 || a.IP.To4() == nil
+		}
+		if !v4 || !v6 {
+			Skip("localhost does not resolve to both an IPv4 and an IPv6 address")
+		}
+		// A wildcard listener accepts both address families on Linux.
+		port := listen(":0")
+		dep := corev1alpha1.ServiceDependency{Host: "localhost", Port: port, DualStack: true}
+		_, err := Run(probeCtx(), dep.Host, dep, mapSecrets{}, fakeObjectReader())
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
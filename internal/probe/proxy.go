@@ -0,0 +1,230 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// ReasonProxyError is reported when the proxy refuses to open a tunnel to the dependency.
+const ReasonProxyError = "ProxyError"
+
+// Proxy is the operator-wide HTTP proxy. It applies to host dependencies that do not
+// set their own proxy; Service dependencies are in-cluster and always connect directly.
+type Proxy struct {
+	// HTTPProxy is used for plain HTTP probes.
+	HTTPProxy string
+	// HTTPSProxy is used for HTTPS probes and for TCP probes, which tunnel through it
+	// with CONNECT.
+	HTTPSProxy string
+	// NoProxy lists the hosts, domain suffixes and CIDRs that are reached directly,
+	// in NO_PROXY syntax.
+	NoProxy string
+	// CredentialsSecret names a Secret, in the namespace of each workload, whose
+	// username and password keys init containers authenticate to the proxy with. The
+	// user info of HTTPProxy and HTTPSProxy is only used by the operator's own probes.
+	CredentialsSecret string
+}
+
+// ProxyFromEnvironment returns the proxy configured by the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables, or their lower-case forms.
+func ProxyFromEnvironment() Proxy {
+	cfg := httpproxy.FromEnvironment()
+	return Proxy{HTTPProxy: cfg.HTTPProxy, HTTPSProxy: cfg.HTTPSProxy, NoProxy: cfg.NoProxy}
+}
+
+// Validate reports whether the proxy URLs can be parsed.
+func (p Proxy) Validate() error {
+	for _, raw := range []string{p.HTTPProxy, p.HTTPSProxy} {
+		if raw == "" {
+			continue
+		}
+		if _, err := parseProxyURL(raw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply returns dep with its effective proxy: the dependency's own proxy when it sets
+// one, otherwise p when dep is a TCP or HTTP probe of a host that NoProxy does not match.
+// Dual-stack dependencies always connect directly. The proxy URL keeps its user info,
+// so the result is only for probes run by the operator itself.
+func (p Proxy) Apply(host string, dep corev1alpha1.ServiceDependency) corev1alpha1.ServiceDependency {
+	if u := p.proxyFor(host, dep); u != nil {
+		dep.Proxy = &corev1alpha1.ProxySpec{URL: u.String()}
+	}
+	return dep
+}
+
+// ApplyToInitContainer is Apply for a dependency that is baked into an init container.
+// The user info of the operator-wide proxy never ends up in the pod spec: the init
+// container authenticates with CredentialsSecret instead, when it is set.
+func (p Proxy) ApplyToInitContainer(host string, dep corev1alpha1.ServiceDependency) corev1alpha1.ServiceDependency {
+	u := p.proxyFor(host, dep)
+	if u == nil {
+		return dep
+	}
+	u.User = nil
+	dep.Proxy = &corev1alpha1.ProxySpec{URL: u.String()}
+	if p.CredentialsSecret != "" {
+		dep.Proxy.CredentialsSecretRef = &corev1alpha1.SecretCredentialsRef{Name: p.CredentialsSecret}
+	}
+	return dep
+}
+
+// proxyFor returns the operator-wide proxy for dep, or nil when dep sets its own proxy
+// or connects directly.
+func (p Proxy) proxyFor(host string, dep corev1alpha1.ServiceDependency) *url.URL {
+	if dep.Proxy != nil || dep.Host == "" || dep.DualStack || !proxyable(dep) {
+		return nil
+	}
+	// Without a request to inspect, the scheme only selects HTTP_PROXY or HTTPS_PROXY.
+	scheme := "https"
+	if dep.HTTPPath != "" && dep.HTTPScheme != "https" {
+		scheme = "http"
+	}
+	cfg := httpproxy.Config{HTTPProxy: p.HTTPProxy, HTTPSProxy: p.HTTPSProxy, NoProxy: p.NoProxy}
+	u, err := cfg.ProxyFunc()(&url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(int(dep.Port)))})
+	if err != nil {
+		return nil
+	}
+	return u
+}
+
+// ProxyURL returns the proxy the dependency's probe goes through, or "" when it
+// connects directly.
+func ProxyURL(dep corev1alpha1.ServiceDependency) string {
+	if dep.Proxy == nil || dep.Proxy.Disabled || dep.DualStack || !proxyable(dep) {
+		return ""
+	}
+	return dep.Proxy.URL
+}
+
+// proxyURL returns ProxyURL(dep) with the username and password of the proxy's
+// credentialsSecretRef, read through secrets, as its user info.
+func proxyURL(ctx context.Context, dep corev1alpha1.ServiceDependency, secrets SecretReader) (string, error) {
+	raw := ProxyURL(dep)
+	if raw == "" || dep.Proxy.CredentialsSecretRef == nil {
+		return raw, nil
+	}
+	u, err := parseProxyURL(raw)
+	if err != nil {
+		return "", err
+	}
+	c, err := readCredentials(ctx, secrets, dep.Proxy.CredentialsSecretRef)
+	if err != nil {
+		return "", err
+	}
+	u.User = url.UserPassword(c.username, c.password)
+	return u.String(), nil
+}
+
+// proxyable reports whether dep is probed with a TCP connection or an HTTP request,
+// the only probes that can go through a proxy.
+func proxyable(dep corev1alpha1.ServiceDependency) bool {
+	return dep.GRPC == nil && dep.Postgres == nil && dep.MySQL == nil && dep.Redis == nil &&
+		dep.Kafka == nil && dep.AMQP == nil && dep.MongoDB == nil && dep.DNS == nil &&
+		dep.TLS == nil && dep.Endpoints == nil && dep.WorkloadRef == nil && dep.JobRef == nil &&
+		dep.ResourceRef == nil
+}
+
+// parseProxyURL parses a proxy URL, defaulting to the http scheme like curl does.
+func parseProxyURL(raw string) (*url.URL, error) {
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q: must be http:// or https:// followed by a host", u.Redacted())
+	}
+	return u, nil
+}
+
+// dialProxy opens a tunnel to addr through the HTTP proxy at rawURL with CONNECT.
+func dialProxy(ctx context.Context, rawURL, addr string) (net.Conn, error) {
+	u, err := parseProxyURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	proxyAddr := u.Host
+	if u.Port() == "" {
+		proxyAddr = net.JoinHostPort(u.Hostname(), map[string]string{"http": "80", "https": "443"}[u.Scheme])
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", proxyAddr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if u.User != nil {
+		password, _ := u.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err := req.Write(conn); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+		return nil, errorf(ReasonProxyError, "proxy %s refused CONNECT to %s: %s", u.Host, addr, resp.Status)
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return conn, nil
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the Redis probe.
+const (
+	ReasonDatasetLoading = "DatasetLoading"
+	ReasonNotMaster      = "NotMaster"
+)
+
+const redisMaxBulkSize = 1 << 20
+
+// Redis sends PING to the Redis server on addr, authenticating first when
+// credentials are configured, and succeeds only on PONG. A server that is still
+// loading its dataset answers -LOADING and is not ready. With requireMaster the
+// probe additionally requires "role:master" in INFO replication.
+func Redis(ctx context.Context, addr string, spec corev1alpha1.RedisProbe, secrets SecretReader) (string, error) {
+	var args []string
+	if ref := spec.CredentialsSecretRef; ref != nil {
+		password, err := secrets.ReadSecretKey(ctx, ref.Name, redisPasswordKey(ref))
+		if err != nil {
+			return "", errorf(ReasonSecretNotFound, "%v", err)
+		}
+		args = []string{"AUTH", password}
+		if ref.UsernameKey != "" {
+			username, err := secrets.ReadSecretKey(ctx, ref.Name, ref.UsernameKey)
+			if err != nil {
+				return "", errorf(ReasonSecretNotFound, "%v", err)
+			}
+			args = []string{"AUTH", username, password}
+		}
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	rc := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if args != nil {
+		if _, err := rc.do(args...); err != nil {
+			if Reason(err) == ReasonServerError {
+				return "", errorf(ReasonAuthenticationFailed, "AUTH failed: %v", err)
+			}
+			return "", err
+		}
+	}
+
+	reply, err := rc.do("PING")
+	if err != nil {
+		return "", err
+	}
+	if reply != "PONG" {
+		return "", errorf(ReasonProtocolError, "unexpected reply %q to PING", reply)
+	}
+	if !spec.RequireMaster {
+		return "", nil
+	}
+
+	info, err := rc.do("INFO", "replication")
+	if err != nil {
+		return "", err
+	}
+	role := redisInfoField(info, "role")
+	if role != "master" {
+		return "", errorf(ReasonNotMaster, "role is %q, not master", role)
+	}
+	return "role master", nil
+}
+
+// redisPasswordKey returns the Secret key holding the Redis password.
+func redisPasswordKey(ref *corev1alpha1.RedisCredentialsRef) string {
+	if ref.PasswordKey == "" {
+		return "password"
+	}
+	return ref.PasswordKey
+}
+
+// redisConn is a minimal RESP client connection.
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// do sends a command and returns its simple string or bulk string reply.
+// Error replies are returned as probe errors.
+func (c *redisConn) do(args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := io.WriteString(c.conn, b.String()); err != nil {
+		return "", err
+	}
+
+	line, err := c.readLine()
+	if err != nil {
+		return "", err
+	}
+	if line == "" {
+		return "", errorf(ReasonProtocolError, "empty reply")
+	}
+	switch line[0] {
+	case '+', ':':
+		return line[1:], nil
+	case '-':
+		return "", redisError(line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n > redisMaxBulkSize {
+			return "", errorf(ReasonProtocolError, "invalid bulk length %q", line[1:])
+		}
+		if n < 0 {
+			return "", nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			return "", err
+		}
+		return string(buf[:n]), nil
+	default:
+		return "", errorf(ReasonProtocolError, "unexpected reply %q", line)
+	}
+}
+
+// readLine reads a CRLF-terminated line without the terminator.
+func (c *redisConn) readLine() (string, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// redisError maps a Redis error reply to a probe reason based on its error code prefix.
+func redisError(msg string) error {
+	code, _, _ := strings.Cut(msg, " ")
+	switch code {
+	case "LOADING":
+		return errorf(ReasonDatasetLoading, "%s", msg)
+	case "NOAUTH", "WRONGPASS":
+		return errorf(ReasonAuthenticationFailed, "%s", msg)
+	default:
+		return errorf(ReasonServerError, "%s", msg)
+	}
+}
+
+// redisInfoField returns the value of field in an INFO reply.
+func redisInfoField(info, field string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), field+":"); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// startFakeRedis accepts one connection and answers each command with the reply
+// returned by respond. Received commands are sent to the returned channel.
+func startFakeRedis(respond func(cmd []string) string) (string, <-chan []string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	cmds := make(chan []string, 8)
+	go func() {
+		conn, err := lis.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			cmd := make([]string, n)
+			for i := range cmd {
+				_, _ = r.ReadString('\n') // $<len>
+				arg, _ := r.ReadString('\n')
+				cmd[i] = strings.TrimRight(arg, "\r\n")
+			}
+			cmds <- cmd
+			if _, err := conn.Write([]byte(respond(cmd))); err != nil {
+				return
+			}
+		}
+	}()
+	return lis.Addr().String(), cmds
+}
+
+func redisBulk(s string) string {
+	return "$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n"
+}
+
+var _ = Describe("Redis", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	It("should be ready when PING answers PONG", func() {
+		addr, _ := startFakeRedis(func([]string) string { return "+PONG\r\n" })
+		_, err := Redis(probeCtx(), addr, corev1alpha1.RedisProbe{}, nil)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report DatasetLoading while the server is loading the RDB", func() {
+		addr, _ := startFakeRedis(func([]string) string {
+			return "-LOADING Redis is loading the dataset in memory\r\n"
+		})
+		_, err := Redis(probeCtx(), addr, corev1alpha1.RedisProbe{}, nil)
+		Expect(Reason(err)).To(Equal(ReasonDatasetLoading))
+		Expect(err.Error()).To(ContainSubstring("loading the dataset"))
+	})
+
+	It("should send AUTH with the Secret's password before PING", func() {
+		addr, cmds := startFakeRedis(func(cmd []string) string {
+			if cmd[0] == "AUTH" {
+				return "+OK\r\n"
+			}
+			return "+PONG\r\n"
+		})
+		spec := corev1alpha1.RedisProbe{CredentialsSecretRef: &corev1alpha1.RedisCredentialsRef{Name: "redis"}}
+		_, err := Redis(probeCtx(), addr, spec, mapSecrets{"redis/password": "s3cret"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(<-cmds).To(Equal([]string{"AUTH", "s3cret"}))
+		Expect(<-cmds).To(Equal([]string{"PING"}))
+	})
+
+	It("should send the ACL username when usernameKey is set", func() {
+		addr, cmds := startFakeRedis(func(cmd []string) string {
+			if cmd[0] == "AUTH" {
+				return "-WRONGPASS invalid username-password pair or user is disabled.\r\n"
+			}
+			return "+PONG\r\n"
+		})
+		spec := corev1alpha1.RedisProbe{CredentialsSecretRef: &corev1alpha1.RedisCredentialsRef{
+			Name: "redis", UsernameKey: "user",
+		}}
+		_, err := Redis(probeCtx(), addr, spec, mapSecrets{"redis/user": "app", "redis/password": "wrong"})
+		Expect(Reason(err)).To(Equal(ReasonAuthenticationFailed))
+		Expect(<-cmds).To(Equal([]string{"AUTH", "app", "wrong"}))
+	})
+
+	Context("with requireMaster", func() {
+		info := func(role string) func([]string) string {
+			return func(cmd []string) string {
+				if cmd[0] == "INFO" {
+					return redisBulk("# Replication\r\nrole:" + role + "\r\nconnected_slaves:0\r\n")
+				}
+				return "+PONG\r\n"
+			}
+		}
+
+		It("should be ready on a master", func() {
+			addr, _ := startFakeRedis(info("master"))
+			detail, err := Redis(probeCtx(), addr, corev1alpha1.RedisProbe{RequireMaster: true}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(detail).To(Equal("role master"))
+		})
+
+		It("should report NotMaster on a replica", func() {
+			addr, _ := startFakeRedis(info("slave"))
+			_, err := Redis(probeCtx(), addr, corev1alpha1.RedisProbe{RequireMaster: true}, nil)
+			Expect(Reason(err)).To(Equal(ReasonNotMaster))
+		})
+	})
+})
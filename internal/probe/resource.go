@@ -0,0 +1,102 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/cel-go/cel"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the resource probe.
+const (
+	// ReasonResourceNotFound is reported when the referenced object, or its kind, does not exist.
+	ReasonResourceNotFound = "ResourceNotFound"
+	// ReasonConditionNotMet is reported when the resourceRef expression is not true.
+	ReasonConditionNotMet = "ConditionNotMet"
+)
+
+// resourceCELEnv declares the variables available to resourceRef expressions.
+var resourceCELEnv = sync.OnceValues(func() (*cel.Env, error) {
+	return cel.NewEnv(
+		cel.Variable("object", cel.DynType),
+		cel.CrossTypeNumericComparisons(true),
+	)
+})
+
+// CompileResourceExpression parses and type-checks a resourceRef expression. The
+// validating webhook uses it to reject invalid expressions before they reach the probes.
+func CompileResourceExpression(expr string) (cel.Program, error) {
+	env, err := resourceCELEnv()
+	if err != nil {
+		return nil, err
+	}
+	return compileBool(env, expr, cel.CostLimit(celCostLimit))
+}
+
+// ForbiddenResource reports whether ref points at a Secret. The operator can read every
+// Secret in the cluster, so a resourceRef to one would let the author of a
+// BootDependency probe Secrets they have no access to.
+func ForbiddenResource(ref corev1alpha1.ResourceRef) bool {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	return err == nil && gv.Group == "" && strings.EqualFold(ref.Kind, "Secret")
+}
+
+// Resource reads the referenced object through objects and succeeds once ref's
+// expression evaluates to true for it. The result of the expression is never reported,
+// since it may contain data from the object.
+func Resource(ctx context.Context, objects ObjectReader, ref corev1alpha1.ResourceRef) (string, error) {
+	if ForbiddenResource(ref) {
+		return "", errorf(ReasonForbidden, "resourceRef may not refer to a Secret")
+	}
+	prg, err := CompileResourceExpression(ref.Expression)
+	if err != nil {
+		return "", errorf(ReasonConditionNotMet, "invalid expression: %v", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(ref.APIVersion)
+	obj.SetKind(ref.Kind)
+	obj.SetNamespace(ref.Namespace)
+	if err := objects.GetObject(ctx, ref.Name, obj); err != nil {
+		switch {
+		case apierrors.IsNotFound(err):
+			return "", errorf(ReasonResourceNotFound, "%s %s not found", ref.Kind, ref.Name)
+		case meta.IsNoMatchError(err):
+			return "", errorf(ReasonResourceNotFound, "%v", err)
+		default:
+			return "", objectError(err, ref.Kind, ref.Name)
+		}
+	}
+
+	out, _, err := prg.Eval(map[string]any{"object": obj.Object})
+	if err != nil {
+		return "", errorf(ReasonConditionNotMet, "expression could not be evaluated for %s %s", ref.Kind, ref.Name)
+	}
+	if ok, isBool := out.Value().(bool); !isBool || !ok {
+		return "", errorf(ReasonConditionNotMet, "expression is not true for %s %s", ref.Kind, ref.Name)
+	}
+	return "", nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("Resource", func() {
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data", Namespace: "default"},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+	flags := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "flags", Namespace: "platform"},
+		Data:       map[string]string{"payments": "enabled"},
+	}
+
+	ref := func(apiVersion, kind, namespace, name, expr string) corev1alpha1.ResourceRef {
+		return corev1alpha1.ResourceRef{APIVersion: apiVersion, Kind: kind, Namespace: namespace, Name: name, Expression: expr}
+	}
+
+	It("should be ready once the expression over the object is true", func() {
+		_, err := Resource(context.Background(), fakeObjectReader(flags),
+			ref("v1", "ConfigMap", "platform", "flags", `object.data.payments == "enabled"`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report ConditionNotMet while the expression is false", func() {
+		_, err := Resource(context.Background(), fakeObjectReader(pvc),
+			ref("v1", "PersistentVolumeClaim", "", "data", `object.status.phase == "Bound"`))
+		Expect(Reason(err)).To(Equal(ReasonConditionNotMet))
+		Expect(err).To(MatchError("expression is not true for PersistentVolumeClaim data"))
+	})
+
+	It("should report ConditionNotMet when a referenced field is missing", func() {
+		_, err := Resource(context.Background(), fakeObjectReader(flags),
+			ref("v1", "ConfigMap", "platform", "flags", `object.data.orders == "enabled"`))
+		Expect(Reason(err)).To(Equal(ReasonConditionNotMet))
+	})
+
+	It("should report ResourceNotFound for a missing object or an unknown kind", func() {
+		_, err := Resource(context.Background(), fakeObjectReader(),
+			ref("v1", "PersistentVolumeClaim", "", "data", `object.status.phase == "Bound"`))
+		Expect(Reason(err)).To(Equal(ReasonResourceNotFound))
+
+		_, err = Resource(context.Background(), fakeObjectReader(),
+			ref("cert-manager.io/v1", "Certificate", "", "payments-tls", `object.status.conditions.exists(c, c.type == "Ready")`))
+		Expect(Reason(err)).To(Equal(ReasonResourceNotFound))
+	})
+
+	It("should not report data read from the object", func() {
+		_, err := Resource(context.Background(), fakeObjectReader(flags),
+			ref("v1", "ConfigMap", "platform", "flags", `object.data.payments == "disabled"`))
+		Expect(err).NotTo(MatchError(ContainSubstring("enabled")))
+
+		_, err = Resource(context.Background(), fakeObjectReader(flags),
+			ref("v1", "ConfigMap", "platform", "flags", `int(object.data.payments) > 0`))
+		Expect(Reason(err)).To(Equal(ReasonConditionNotMet))
+		Expect(err).NotTo(MatchError(ContainSubstring("enabled")))
+	})
+
+	It("should refuse to read Secrets", func() {
+		secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default"}}
+		_, err := Resource(context.Background(), fakeObjectReader(secret),
+			ref("v1", "Secret", "", "db", `has(object.data)`))
+		Expect(Reason(err)).To(Equal(ReasonForbidden))
+		Expect(ForbiddenResource(ref("v1", "secret", "", "db", "true"))).To(BeTrue())
+		Expect(ForbiddenResource(ref("bitnami.com/v1alpha1", "SealedSecret", "", "db", "true"))).To(BeFalse())
+	})
+
+	It("should stop evaluating an expression that exceeds the cost limit", func() {
+		big := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "big", Namespace: "default"}, Data: map[string]string{}}
+		for i := range 200 {
+			big.Data[fmt.Sprintf("key-%d", i)] = "x"
+		}
+		_, err := Resource(context.Background(), fakeObjectReader(big),
+			ref("v1", "ConfigMap", "", "big", `object.data.all(a, object.data.all(b, object.data.all(c, a != "")))`))
+		Expect(Reason(err)).To(Equal(ReasonConditionNotMet))
+		Expect(err).To(MatchError("expression could not be evaluated for ConfigMap big"))
+	})
+
+	It("should reject expressions that do not compile or are not boolean", func() {
+		_, err := CompileResourceExpression(`object.status.phase ==`)
+		Expect(err).To(HaveOccurred())
+		_, err = CompileResourceExpression(`object.status.phase`)
+		Expect(err).To(MatchError(ContainSubstring("expression must evaluate to bool")))
+		_, err = CompileResourceExpression(`object.status.conditions.exists(c, c.type == "Ready" && c.status == "True")`)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,284 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// SecretReader resolves a single key of a Secret or ConfigMap in the dependency's
+// namespace.
+type SecretReader interface {
+	ReadSecretKey(ctx context.Context, name, key string) (string, error)
+	ReadConfigMapKey(ctx context.Context, name, key string) (string, error)
+}
+
+// EnvSecrets reads Secret and ConfigMap keys from environment variables named by
+// SecretEnvName and ConfigMapEnvName. The mutating webhook populates those variables
+// through secretKeyRef and configMapKeyRef, so secret values never appear in the
+// init container's command.
+type EnvSecrets struct{}
+
+// ReadSecretKey implements SecretReader.
+func (EnvSecrets) ReadSecretKey(_ context.Context, name, key string) (string, error) {
+	env := SecretEnvName(name, key)
+	v, ok := os.LookupEnv(env)
+	if !ok {
+		return "", fmt.Errorf("secret %s key %s: %s is not set", name, key, env)
+	}
+	return v, nil
+}
+
+// ReadConfigMapKey implements SecretReader.
+func (EnvSecrets) ReadConfigMapKey(_ context.Context, name, key string) (string, error) {
+	env := ConfigMapEnvName(name, key)
+	v, ok := os.LookupEnv(env)
+	if !ok {
+		return "", fmt.Errorf("configmap %s key %s: %s is not set", name, key, env)
+	}
+	return v, nil
+}
+
+// SecretEnvName returns the environment variable that carries the given Secret key
+// inside an init container.
+func SecretEnvName(name, key string) string {
+	return envName("BOOTCHAIN_SECRET_", name, key)
+}
+
+// ConfigMapEnvName returns the environment variable that carries the given ConfigMap
+// key inside an init container.
+func ConfigMapEnvName(name, key string) string {
+	return envName("BOOTCHAIN_CONFIGMAP_", name, key)
+}
+
+// envName maps name and key to an environment variable. Mapping every other character
+// to '_' folds distinct pairs such as a-b/c and a/b-c together, so a short hash of the
+// exact name and key is appended; neither can contain '/'.
+func envName(prefix, name, key string) string {
+	upper := strings.ToUpper(name + "_" + key)
+	sum := sha256.Sum256([]byte(name + "/" + key))
+	return prefix + strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, upper) + "_" + strings.ToUpper(hex.EncodeToString(sum[:4]))
+}
+
+// SecretKeyRef identifies a single key of a Secret.
+type SecretKeyRef struct {
+	Name string
+	Key  string
+}
+
+// ConfigMapKeyRef identifies a single key of a ConfigMap.
+type ConfigMapKeyRef struct {
+	Name string
+	Key  string
+}
+
+// SecretRefs returns every Secret key the dependency's probe reads, so that the
+// webhook can expose them to the init container as environment variables.
+func SecretRefs(dep corev1alpha1.ServiceDependency) []SecretKeyRef {
+	var refs []SecretKeyRef
+	if dep.Postgres != nil {
+		refs = append(refs, credentialRefs(dep.Postgres.CredentialsSecretRef)...)
+	}
+	if dep.MySQL != nil {
+		refs = append(refs, credentialRefs(dep.MySQL.CredentialsSecretRef)...)
+	}
+	if dep.AMQP != nil {
+		refs = append(refs, credentialRefs(dep.AMQP.CredentialsSecretRef)...)
+	}
+	if dep.Redis != nil && dep.Redis.CredentialsSecretRef != nil {
+		ref := dep.Redis.CredentialsSecretRef
+		if ref.UsernameKey != "" {
+			refs = append(refs, SecretKeyRef{Name: ref.Name, Key: ref.UsernameKey})
+		}
+		refs = append(refs, SecretKeyRef{Name: ref.Name, Key: redisPasswordKey(ref)})
+	}
+	for _, ca := range caBundleRefs(dep) {
+		if ref := ca.SecretKeyRef; ref != nil {
+			refs = append(refs, SecretKeyRef{Name: ref.Name, Key: ref.Key})
+		}
+	}
+	if ref := dep.ClientCertSecretRef; ref != nil {
+		refs = append(refs,
+			SecretKeyRef{Name: ref.Name, Key: clientCertKey(ref)},
+			SecretKeyRef{Name: ref.Name, Key: clientKeyKey(ref)},
+		)
+	}
+	if dep.Proxy != nil {
+		refs = append(refs, credentialRefs(dep.Proxy.CredentialsSecretRef)...)
+	}
+	for _, h := range dep.HTTPHeaders {
+		if h.ValueFrom != nil && h.ValueFrom.SecretKeyRef != nil {
+			refs = append(refs, SecretKeyRef{Name: h.ValueFrom.SecretKeyRef.Name, Key: h.ValueFrom.SecretKeyRef.Key})
+		}
+	}
+	return refs
+}
+
+// ConfigMapRefs returns every ConfigMap key the dependency's probe reads, so that the
+// webhook can expose them to the init container as environment variables.
+func ConfigMapRefs(dep corev1alpha1.ServiceDependency) []ConfigMapKeyRef {
+	var refs []ConfigMapKeyRef
+	for _, ca := range caBundleRefs(dep) {
+		if ref := ca.ConfigMapKeyRef; ref != nil {
+			refs = append(refs, ConfigMapKeyRef{Name: ref.Name, Key: ref.Key})
+		}
+	}
+	for _, h := range dep.HTTPHeaders {
+		if h.ValueFrom != nil && h.ValueFrom.ConfigMapKeyRef != nil {
+			refs = append(refs, ConfigMapKeyRef{Name: h.ValueFrom.ConfigMapKeyRef.Name, Key: h.ValueFrom.ConfigMapKeyRef.Key})
+		}
+	}
+	return refs
+}
+
+// headerValue returns the value of h, reading it through secrets when it comes from
+// a Secret or ConfigMap.
+func headerValue(ctx context.Context, h corev1alpha1.HTTPHeader, secrets SecretReader) (string, error) {
+	switch {
+	case h.ValueFrom == nil:
+		return h.Value, nil
+	case h.ValueFrom.SecretKeyRef != nil:
+		ref := h.ValueFrom.SecretKeyRef
+		v, err := secrets.ReadSecretKey(ctx, ref.Name, ref.Key)
+		if err != nil {
+			return "", errorf(ReasonSecretNotFound, "header %s: %v", h.Name, err)
+		}
+		return v, nil
+	case h.ValueFrom.ConfigMapKeyRef != nil:
+		ref := h.ValueFrom.ConfigMapKeyRef
+		v, err := secrets.ReadConfigMapKey(ctx, ref.Name, ref.Key)
+		if err != nil {
+			return "", errorf(ReasonConfigMapNotFound, "header %s: %v", h.Name, err)
+		}
+		return v, nil
+	default:
+		return "", nil
+	}
+}
+
+// caBundle returns the PEM bundle referenced by ref, reading it through secrets.
+func caBundle(ctx context.Context, ref *corev1alpha1.CABundleRef, secrets SecretReader) (string, error) {
+	switch {
+	case ref.SecretKeyRef != nil:
+		v, err := secrets.ReadSecretKey(ctx, ref.SecretKeyRef.Name, ref.SecretKeyRef.Key)
+		if err != nil {
+			return "", errorf(ReasonSecretNotFound, "CA bundle: %v", err)
+		}
+		return v, nil
+	case ref.ConfigMapKeyRef != nil:
+		v, err := secrets.ReadConfigMapKey(ctx, ref.ConfigMapKeyRef.Name, ref.ConfigMapKeyRef.Key)
+		if err != nil {
+			return "", errorf(ReasonConfigMapNotFound, "CA bundle: %v", err)
+		}
+		return v, nil
+	default:
+		return "", nil
+	}
+}
+
+// caBundleRefs returns the CA bundles the dependency's probe reads: the one of an
+// HTTPS probe and the one of a TLS probe.
+func caBundleRefs(dep corev1alpha1.ServiceDependency) []*corev1alpha1.CABundleRef {
+	var refs []*corev1alpha1.CABundleRef
+	if dep.CABundleRef != nil {
+		refs = append(refs, dep.CABundleRef)
+	}
+	if dep.TLS != nil && dep.TLS.CABundleRef != nil {
+		refs = append(refs, dep.TLS.CABundleRef)
+	}
+	return refs
+}
+
+// credentialRefs returns the username and password keys referenced by ref.
+func credentialRefs(ref *corev1alpha1.SecretCredentialsRef) []SecretKeyRef {
+	if ref == nil {
+		return nil
+	}
+	return []SecretKeyRef{
+		{Name: ref.Name, Key: usernameKey(ref)},
+		{Name: ref.Name, Key: passwordKey(ref)},
+	}
+}
+
+func usernameKey(ref *corev1alpha1.SecretCredentialsRef) string {
+	if ref.UsernameKey == "" {
+		return "username"
+	}
+	return ref.UsernameKey
+}
+
+func passwordKey(ref *corev1alpha1.SecretCredentialsRef) string {
+	if ref.PasswordKey == "" {
+		return "password"
+	}
+	return ref.PasswordKey
+}
+
+func clientCertKey(ref *corev1alpha1.ClientCertSecretRef) string {
+	if ref.CertKey == "" {
+		return "tls.crt"
+	}
+	return ref.CertKey
+}
+
+func clientKeyKey(ref *corev1alpha1.ClientCertSecretRef) string {
+	if ref.KeyKey == "" {
+		return "tls.key"
+	}
+	return ref.KeyKey
+}
+
+// authCredentials holds a resolved username and password.
+type authCredentials struct {
+	username string
+	password string
+}
+
+// readCredentials resolves ref through secrets.
+func readCredentials(ctx context.Context, secrets SecretReader, ref *corev1alpha1.SecretCredentialsRef) (authCredentials, error) {
+	var c authCredentials
+	var err error
+	if c.username, err = secrets.ReadSecretKey(ctx, ref.Name, usernameKey(ref)); err != nil {
+		return c, errorf(ReasonSecretNotFound, "%v", err)
+	}
+	if c.password, err = secrets.ReadSecretKey(ctx, ref.Name, passwordKey(ref)); err != nil {
+		return c, errorf(ReasonSecretNotFound, "%v", err)
+	}
+	return c, nil
+}
+
+// DependencyEnv is the environment variable through which the mutating webhook
+// hands the JSON-encoded ServiceDependency to the bootchain-probe binary.
+const DependencyEnv = "BOOTCHAIN_DEPENDENCY"
+
+// TargetEnv is the environment variable holding the host the bootchain-probe
+// binary connects to. In-cluster services are addressed by their short name there,
+// so it differs from the FQDN the controller uses; the webhook sets the serverName of
+// a TLS probe to that FQDN so that both verify the same name.
+const TargetEnv = "BOOTCHAIN_TARGET"
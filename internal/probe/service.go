@@ -0,0 +1,68 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Reasons reported when a named Service port cannot be resolved.
+const (
+	// ReasonServiceNotFound is reported when the Service of a portName dependency does not exist.
+	ReasonServiceNotFound = "ServiceNotFound"
+	// ReasonPortNotFound is reported when the Service has no port with the requested name.
+	ReasonPortNotFound = "PortNotFound"
+)
+
+// ServicePort reads service in namespace through objects and returns the number of its
+// port called name. An empty namespace is the reader's own.
+func ServicePort(ctx context.Context, objects ObjectReader, namespace, service, name string) (int32, error) {
+	var svc corev1.Service
+	svc.Namespace = namespace
+	if err := objects.GetObject(ctx, service, &svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return 0, errorf(ReasonServiceNotFound, "Service %s not found", qualifiedName(namespace, service))
+		}
+		return 0, objectError(err, "Service", qualifiedName(namespace, service))
+	}
+	port, ok := NamedPort(&svc, name)
+	if !ok {
+		return 0, errorf(ReasonPortNotFound, "Service %s has no port named %q", qualifiedName(namespace, service), name)
+	}
+	return port, nil
+}
+
+// qualifiedName returns namespace/name, or name alone when namespace is empty.
+func qualifiedName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// NamedPort returns the number of the port called name in svc's spec.
+func NamedPort(svc *corev1.Service, name string) (int32, bool) {
+	for _, p := range svc.Spec.Ports {
+		if p.Name == name {
+			return p.Port, true
+		}
+	}
+	return 0, false
+}
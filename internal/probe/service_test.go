@@ -0,0 +1,86 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("ServicePort", func() {
+	service := func(ports ...corev1.ServicePort) *corev1.Service {
+		return &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+	}
+
+	It("should resolve a port by name", func() {
+		objects := fakeObjectReader(service(
+			corev1.ServicePort{Name: "http", Port: 8080},
+			corev1.ServicePort{Name: "grpc", Port: 9090},
+		))
+		port, err := ServicePort(context.Background(), objects, "", "api", "grpc")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(port).To(Equal(int32(9090)))
+	})
+
+	It("should report PortNotFound when the Service has no such port", func() {
+		objects := fakeObjectReader(service(corev1.ServicePort{Name: "http", Port: 8080}))
+		_, err := ServicePort(context.Background(), objects, "", "api", "grpc")
+		Expect(Reason(err)).To(Equal(ReasonPortNotFound))
+		Expect(err).To(MatchError(`Service api has no port named "grpc"`))
+	})
+
+	It("should report ServiceNotFound for a missing Service", func() {
+		_, err := ServicePort(context.Background(), fakeObjectReader(), "", "api", "http")
+		Expect(Reason(err)).To(Equal(ReasonServiceNotFound))
+	})
+
+	It("should read a Service in another namespace", func() {
+		svc := service(corev1.ServicePort{Name: "kafka", Port: 9092})
+		svc.Namespace = "platform"
+		objects := fakeObjectReader(svc)
+		port, err := ServicePort(context.Background(), objects, "platform", "api", "kafka")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(port).To(Equal(int32(9092)))
+
+		_, err = ServicePort(context.Background(), objects, "", "api", "kafka")
+		Expect(err).To(MatchError("Service api not found"))
+		_, err = ServicePort(context.Background(), fakeObjectReader(), "platform", "api", "kafka")
+		Expect(err).To(MatchError("Service platform/api not found"))
+	})
+
+	It("should probe the resolved port when Run is given a portName", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(lis.Close)
+		port := int32(lis.Addr().(*net.TCPAddr).Port)
+
+		objects := fakeObjectReader(service(corev1.ServicePort{Name: "http", Port: port}))
+		dep := corev1alpha1.ServiceDependency{Service: "api", PortName: "http"}
+		_, err = Run(context.Background(), "127.0.0.1", dep, nil, objects)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,133 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the TLS probe.
+const (
+	// ReasonTLSHandshakeFailed is reported when the TLS handshake cannot be completed.
+	ReasonTLSHandshakeFailed = "TLSHandshakeFailed"
+	// ReasonCertificateInvalid is reported when the certificate chain does not verify
+	// or the certificate is not valid for the server name.
+	ReasonCertificateInvalid = "CertificateInvalid"
+	// ReasonCertificateExpiring is reported when the certificate expires within the
+	// configured minimum remaining validity.
+	ReasonCertificateExpiring = "CertificateExpiring"
+	// ReasonCertificateMismatch is reported when a required subject alternative name
+	// or the issuer does not match.
+	ReasonCertificateMismatch = "CertificateMismatch"
+	// ReasonALPNMismatch is reported when the server negotiates none of the offered
+	// ALPN protocols.
+	ReasonALPNMismatch = "ALPNMismatch"
+)
+
+// TLS completes a TLS handshake with the server on addr and checks the presented
+// certificate against spec. host is the default server name. On success it returns a
+// summary of the leaf certificate including its expiry date.
+func TLS(ctx context.Context, addr, host string, spec corev1alpha1.TLSProbe, secrets SecretReader) (string, error) {
+	serverName := spec.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+	var minValidity time.Duration
+	if spec.MinRemainingValidity != "" {
+		var err error
+		if minValidity, err = time.ParseDuration(spec.MinRemainingValidity); err != nil {
+			return "", fmt.Errorf("invalid minRemainingValidity: %w", err)
+		}
+	}
+	var roots *x509.CertPool
+	if ref := spec.CABundleRef; ref != nil {
+		bundle, err := caBundle(ctx, ref, secrets)
+		if err != nil {
+			return "", err
+		}
+		roots = x509.NewCertPool()
+		if !roots.AppendCertsFromPEM([]byte(bundle)) {
+			return "", errorf(ReasonCertificateInvalid, "caBundleRef holds no PEM certificates")
+		}
+	}
+
+	var d net.Dialer
+	raw, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = raw.Close() }()
+
+	// Verification is done below, after the handshake, so that each failed assertion
+	// is reported with its own reason.
+	conn := tls.Client(raw, &tls.Config{
+		ServerName:         serverName,
+		NextProtos:         spec.ALPNProtocols,
+		InsecureSkipVerify: true, //nolint:gosec
+	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return "", errorf(ReasonTLSHandshakeFailed, "TLS handshake failed: %v", err)
+	}
+	state := conn.ConnectionState()
+
+	leaf := state.PeerCertificates[0]
+	if !spec.Insecure {
+		opts := x509.VerifyOptions{Roots: roots, Intermediates: x509.NewCertPool()}
+		for _, cert := range state.PeerCertificates[1:] {
+			opts.Intermediates.AddCert(cert)
+		}
+		if _, err := leaf.Verify(opts); err != nil {
+			return "", errorf(ReasonCertificateInvalid, "%v", err)
+		}
+		if err := leaf.VerifyHostname(serverName); err != nil {
+			return "", errorf(ReasonCertificateInvalid, "%v", err)
+		}
+	}
+	for _, name := range spec.SubjectAltNames {
+		if err := leaf.VerifyHostname(name); err != nil {
+			return "", errorf(ReasonCertificateMismatch, "certificate does not cover %s", name)
+		}
+	}
+	if spec.Issuer != "" && spec.Issuer != leaf.Issuer.CommonName && spec.Issuer != leaf.Issuer.String() {
+		return "", errorf(ReasonCertificateMismatch, "certificate issued by %q, expected %q", leaf.Issuer.String(), spec.Issuer)
+	}
+
+	expires := leaf.NotAfter.UTC().Format(time.RFC3339)
+	switch remaining := time.Until(leaf.NotAfter); {
+	case remaining <= 0:
+		return "", errorf(ReasonCertificateExpiring, "certificate expired %s", expires)
+	case remaining < minValidity:
+		return "", errorf(ReasonCertificateExpiring, "certificate expires %s, in less than %s", expires, spec.MinRemainingValidity)
+	}
+	if len(spec.ALPNProtocols) > 0 && state.NegotiatedProtocol == "" {
+		return "", errorf(ReasonALPNMismatch, "server negotiated none of %s", strings.Join(spec.ALPNProtocols, ", "))
+	}
+
+	detail := fmt.Sprintf("%s issued by %s, expires %s", leaf.Subject.String(), leaf.Issuer.String(), expires)
+	if state.NegotiatedProtocol != "" {
+		detail += ", ALPN " + state.NegotiatedProtocol
+	}
+	return detail, nil
+}
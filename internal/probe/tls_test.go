@@ -0,0 +1,207 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// testCA is a self-signed certificate authority for TLS tests.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+func newTestCA() testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Bootchain Test CA", Organization: []string{"bootchain"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	Expect(err).NotTo(HaveOccurred())
+	cert, err := x509.ParseCertificate(der)
+	Expect(err).NotTo(HaveOccurred())
+	return testCA{cert: cert, key: key, pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))}
+}
+
+// issue returns a certificate for dnsNames and 127.0.0.1 that expires after validFor.
+// It is valid for both server and client authentication.
+func (ca testCA) issue(validFor time.Duration, dnsNames ...string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).NotTo(HaveOccurred())
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "db.example.internal"},
+		DNSNames:     dnsNames,
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	Expect(err).NotTo(HaveOccurred())
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// startFakeTLS accepts connections and completes the TLS handshake with cert.
+func startFakeTLS(cert tls.Certificate, nextProtos ...string) string {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}, NextProtos: nextProtos})
+	Expect(err).NotTo(HaveOccurred())
+	DeferCleanup(lis.Close)
+
+	go func() {
+		for {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.(*tls.Conn).Handshake()
+			_ = conn.Close()
+		}
+	}()
+	return lis.Addr().String()
+}
+
+var _ = Describe("TLS", func() {
+	probeCtx := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		DeferCleanup(cancel)
+		return ctx
+	}
+
+	var ca testCA
+	var secrets mapSecrets
+	var caRef *corev1alpha1.CABundleRef
+	BeforeEach(func() {
+		ca = newTestCA()
+		secrets = mapSecrets{"db-ca/ca.crt": ca.pem}
+		caRef = &corev1alpha1.CABundleRef{SecretKeyRef: &corev1alpha1.SecretKeySelector{Name: "db-ca", Key: "ca.crt"}}
+	})
+
+	It("should verify the chain against the CA bundle and report the expiry", func() {
+		cert := ca.issue(90*24*time.Hour, "db.example.internal")
+		addr := startFakeTLS(cert, "h2")
+		spec := corev1alpha1.TLSProbe{
+			ServerName:           "db.example.internal",
+			SubjectAltNames:      []string{"db.example.internal", "127.0.0.1"},
+			MinRemainingValidity: "720h",
+			Issuer:               "Bootchain Test CA",
+			CABundleRef:          caRef,
+			ALPNProtocols:        []string{"h2", "http/1.1"},
+		}
+		detail, err := TLS(probeCtx(), addr, "127.0.0.1", spec, secrets)
+		Expect(err).NotTo(HaveOccurred())
+		leaf, _ := x509.ParseCertificate(cert.Certificate[0])
+		Expect(detail).To(Equal("CN=db.example.internal issued by CN=Bootchain Test CA,O=bootchain, expires " +
+			leaf.NotAfter.UTC().Format(time.RFC3339) + ", ALPN h2"))
+	})
+
+	It("should read the CA bundle from a ConfigMap", func() {
+		addr := startFakeTLS(ca.issue(time.Hour, "db.example.internal"))
+		secrets["configmap/trust-bundle/ca.crt"] = ca.pem
+		spec := corev1alpha1.TLSProbe{CABundleRef: &corev1alpha1.CABundleRef{
+			ConfigMapKeyRef: &corev1alpha1.ConfigMapKeySelector{Name: "trust-bundle", Key: "ca.crt"},
+		}}
+		_, err := TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(err).NotTo(HaveOccurred())
+
+		delete(secrets, "configmap/trust-bundle/ca.crt")
+		_, err = TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonConfigMapNotFound))
+	})
+
+	It("should report CertificateInvalid for an unknown authority", func() {
+		addr := startFakeTLS(ca.issue(time.Hour, "db.example.internal"))
+		_, err := TLS(probeCtx(), addr, "db.example.internal", corev1alpha1.TLSProbe{}, secrets)
+		Expect(Reason(err)).To(Equal(ReasonCertificateInvalid))
+	})
+
+	It("should report CertificateInvalid when the server name is not covered", func() {
+		addr := startFakeTLS(ca.issue(time.Hour, "db.example.internal"))
+		spec := corev1alpha1.TLSProbe{CABundleRef: caRef}
+		_, err := TLS(probeCtx(), addr, "other.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonCertificateInvalid))
+	})
+
+	It("should report CertificateExpiring within the minimum remaining validity", func() {
+		addr := startFakeTLS(ca.issue(48*time.Hour, "db.example.internal"))
+		spec := corev1alpha1.TLSProbe{CABundleRef: caRef, MinRemainingValidity: "168h"}
+		_, err := TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonCertificateExpiring))
+		Expect(err.Error()).To(ContainSubstring("in less than 168h"))
+	})
+
+	It("should still check SANs and issuer when insecure is set", func() {
+		addr := startFakeTLS(ca.issue(time.Hour, "db.example.internal"))
+		_, err := TLS(probeCtx(), addr, "db.example.internal", corev1alpha1.TLSProbe{Insecure: true}, secrets)
+		Expect(err).NotTo(HaveOccurred())
+
+		spec := corev1alpha1.TLSProbe{Insecure: true, SubjectAltNames: []string{"replica.example.internal"}}
+		_, err = TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonCertificateMismatch))
+
+		spec = corev1alpha1.TLSProbe{Insecure: true, Issuer: "Other CA"}
+		_, err = TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonCertificateMismatch))
+	})
+
+	It("should report ALPNMismatch when the server negotiates no protocol", func() {
+		addr := startFakeTLS(ca.issue(time.Hour, "db.example.internal"))
+		spec := corev1alpha1.TLSProbe{CABundleRef: caRef, ALPNProtocols: []string{"h2"}}
+		_, err := TLS(probeCtx(), addr, "db.example.internal", spec, secrets)
+		Expect(Reason(err)).To(Equal(ReasonALPNMismatch))
+	})
+
+	It("should report TLSHandshakeFailed for a plaintext server", func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(lis.Close)
+		go func() {
+			conn, err := lis.Accept()
+			if err != nil {
+				return
+			}
+			_, _ = conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+			_ = conn.Close()
+		}()
+		_, err = TLS(probeCtx(), lis.Addr().String(), "db.example.internal", corev1alpha1.TLSProbe{}, secrets)
+		Expect(Reason(err)).To(Equal(ReasonTLSHandshakeFailed))
+	})
+})
@@ -0,0 +1,125 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// Reasons reported by the workload probe.
+const (
+	// ReasonWorkloadNotFound is reported when the referenced workload does not exist.
+	ReasonWorkloadNotFound = "WorkloadNotFound"
+	// ReasonRolloutInProgress is reported while the workload's rollout is not complete.
+	ReasonRolloutInProgress = "RolloutInProgress"
+	// ReasonForbidden is reported when the service account may not read the object.
+	ReasonForbidden = "Forbidden"
+)
+
+// ObjectReader reads objects from the dependency's namespace, for probes that check
+// cluster state instead of a network endpoint. GetObject reads from obj's namespace
+// instead when it is already set, and ListObjects from namespace when it is not empty.
+type ObjectReader interface {
+	GetObject(ctx context.Context, name string, obj client.Object) error
+	ListObjects(ctx context.Context, namespace string, list client.ObjectList, selector labels.Selector) error
+}
+
+// Workload reads the referenced workload through objects and succeeds once its rollout
+// is complete: the latest generation has been observed and every desired replica is
+// updated and available, as kubectl rollout status reports it.
+func Workload(ctx context.Context, objects ObjectReader, ref corev1alpha1.WorkloadRef) (string, error) {
+	var obj client.Object
+	switch ref.Kind {
+	case "Deployment":
+		obj = &appsv1.Deployment{}
+	case "StatefulSet":
+		obj = &appsv1.StatefulSet{}
+	case "DaemonSet":
+		obj = &appsv1.DaemonSet{}
+	default:
+		return "", fmt.Errorf("unsupported workload kind %q", ref.Kind)
+	}
+	if err := objects.GetObject(ctx, ref.Name, obj); err != nil {
+		return "", objectError(err, ref.Kind, ref.Name)
+	}
+
+	var desired, updated, available int32
+	rollingUpdate := true
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		desired = 1
+		if w.Spec.Replicas != nil {
+			desired = *w.Spec.Replicas
+		}
+		if w.Status.ObservedGeneration < w.Generation {
+			return "", errorf(ReasonRolloutInProgress, "waiting for %s %s spec update to be observed", ref.Kind, ref.Name)
+		}
+		if w.Status.Replicas > w.Status.UpdatedReplicas {
+			return "", errorf(ReasonRolloutInProgress, "%d old replicas pending termination", w.Status.Replicas-w.Status.UpdatedReplicas)
+		}
+		updated, available = w.Status.UpdatedReplicas, w.Status.AvailableReplicas
+	case *appsv1.StatefulSet:
+		desired = 1
+		if w.Spec.Replicas != nil {
+			desired = *w.Spec.Replicas
+		}
+		if w.Status.ObservedGeneration < w.Generation {
+			return "", errorf(ReasonRolloutInProgress, "waiting for %s %s spec update to be observed", ref.Kind, ref.Name)
+		}
+		// Partitioned and OnDelete updates are driven by the user, so only
+		// availability is checked for them.
+		rollingUpdate = w.Spec.UpdateStrategy.Type != appsv1.OnDeleteStatefulSetStrategyType &&
+			(w.Spec.UpdateStrategy.RollingUpdate == nil || w.Spec.UpdateStrategy.RollingUpdate.Partition == nil ||
+				*w.Spec.UpdateStrategy.RollingUpdate.Partition == 0)
+		updated, available = w.Status.UpdatedReplicas, w.Status.AvailableReplicas
+	case *appsv1.DaemonSet:
+		if w.Status.ObservedGeneration < w.Generation {
+			return "", errorf(ReasonRolloutInProgress, "waiting for %s %s spec update to be observed", ref.Kind, ref.Name)
+		}
+		rollingUpdate = w.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType
+		desired = w.Status.DesiredNumberScheduled
+		updated, available = w.Status.UpdatedNumberScheduled, w.Status.NumberAvailable
+	}
+
+	if rollingUpdate && updated < desired {
+		return "", errorf(ReasonRolloutInProgress, "%d of %d replicas updated", updated, desired)
+	}
+	if available < desired {
+		return "", errorf(ReasonRolloutInProgress, "%d of %d replicas available", available, desired)
+	}
+	return fmt.Sprintf("%d/%d replicas available", available, desired), nil
+}
+
+// objectError maps a failed read of the named object to a probe error.
+func objectError(err error, kind, name string) error {
+	switch {
+	case apierrors.IsNotFound(err):
+		return errorf(ReasonWorkloadNotFound, "%s %s not found", kind, name)
+	case apierrors.IsForbidden(err):
+		return errorf(ReasonForbidden, "%v", err)
+	default:
+		return err
+	}
+}
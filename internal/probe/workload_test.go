@@ -0,0 +1,152 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probe
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// fakeObjectReader returns an ObjectReader that serves objs.
+func fakeObjectReader(objs ...client.Object) ObjectReader {
+	return fakeObjects{client: fake.NewClientBuilder().WithObjects(objs...).WithStatusSubresource(objs...).Build()}
+}
+
+// fakeObjects serves objects from a fake client, defaulting to the "default" namespace.
+type fakeObjects struct {
+	client client.Client
+}
+
+func (f fakeObjects) GetObject(ctx context.Context, name string, obj client.Object) error {
+	namespace := obj.GetNamespace()
+	if namespace == "" {
+		namespace = "default"
+	}
+	return f.client.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)
+}
+
+func (f fakeObjects) ListObjects(ctx context.Context, namespace string, list client.ObjectList, selector labels.Selector) error {
+	if namespace == "" {
+		namespace = "default"
+	}
+	return f.client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector})
+}
+
+// forbiddenObjects rejects every read, like an API server denying the service account.
+type forbiddenObjects struct{}
+
+func (forbiddenObjects) GetObject(_ context.Context, name string, _ client.Object) error {
+	return apierrors.NewForbidden(schema.GroupResource{Group: "apps", Resource: "deployments"}, name, nil)
+}
+
+func (forbiddenObjects) ListObjects(_ context.Context, _ string, _ client.ObjectList, _ labels.Selector) error {
+	return apierrors.NewForbidden(schema.GroupResource{Group: "batch", Resource: "jobs"}, "", nil)
+}
+
+var _ = Describe("Workload", func() {
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2}
+	}
+	deployment := func(status appsv1.DeploymentStatus) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: meta("postgres"),
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](3)},
+			Status:     status,
+		}
+	}
+	ref := func(kind, name string) corev1alpha1.WorkloadRef {
+		return corev1alpha1.WorkloadRef{Kind: kind, Name: name}
+	}
+
+	It("should be ready once a Deployment rollout is complete", func() {
+		d := deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3})
+		detail, err := Workload(context.Background(), fakeObjectReader(d), ref("Deployment", "postgres"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("3/3 replicas available"))
+	})
+
+	It("should report RolloutInProgress until the new generation is observed", func() {
+		d := deployment(appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3})
+		_, err := Workload(context.Background(), fakeObjectReader(d), ref("Deployment", "postgres"))
+		Expect(Reason(err)).To(Equal(ReasonRolloutInProgress))
+	})
+
+	It("should report RolloutInProgress while old replicas remain or new ones are unavailable", func() {
+		d := deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 4, UpdatedReplicas: 3, AvailableReplicas: 3})
+		_, err := Workload(context.Background(), fakeObjectReader(d), ref("Deployment", "postgres"))
+		Expect(err).To(MatchError("1 old replicas pending termination"))
+
+		d = deployment(appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2})
+		_, err = Workload(context.Background(), fakeObjectReader(d), ref("Deployment", "postgres"))
+		Expect(Reason(err)).To(Equal(ReasonRolloutInProgress))
+		Expect(err).To(MatchError("2 of 3 replicas available"))
+	})
+
+	It("should only require availability for a partitioned StatefulSet", func() {
+		s := &appsv1.StatefulSet{
+			ObjectMeta: meta("kafka"),
+			Spec: appsv1.StatefulSetSpec{
+				Replicas: ptr.To[int32](3),
+				UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+					Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: ptr.To[int32](2)},
+				},
+			},
+			Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, UpdatedReplicas: 1, AvailableReplicas: 3},
+		}
+		detail, err := Workload(context.Background(), fakeObjectReader(s), ref("StatefulSet", "kafka"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(detail).To(Equal("3/3 replicas available"))
+	})
+
+	It("should compare a DaemonSet against its desired number of scheduled pods", func() {
+		ds := &appsv1.DaemonSet{
+			ObjectMeta: meta("node-agent"),
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     2,
+				DesiredNumberScheduled: 5,
+				UpdatedNumberScheduled: 4,
+				NumberAvailable:        5,
+			},
+		}
+		_, err := Workload(context.Background(), fakeObjectReader(ds), ref("DaemonSet", "node-agent"))
+		Expect(err).To(MatchError("4 of 5 replicas updated"))
+	})
+
+	It("should report WorkloadNotFound for a missing workload", func() {
+		_, err := Workload(context.Background(), fakeObjectReader(), ref("Deployment", "postgres"))
+		Expect(Reason(err)).To(Equal(ReasonWorkloadNotFound))
+		Expect(err).To(MatchError("Deployment postgres not found"))
+	})
+
+	It("should report Forbidden when the workload may not be read", func() {
+		_, err := Workload(context.Background(), forbiddenObjects{}, ref("Deployment", "postgres"))
+		Expect(Reason(err)).To(Equal(ReasonForbidden))
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// DependsOnAnnotation declares dependencies inline on a workload, without a
+// BootDependency, as a comma-separated list of <name>:<port> entries and http(s) URLs,
+// e.g. "postgres:5432,https://auth/healthz".
+const DependsOnAnnotation = "bootchain.ruicoelho.dev/depends-on"
+
+// annotationDependencies returns the dependencies declared by the DependsOnAnnotation
+// of obj, or nil when it has none.
+func annotationDependencies(obj client.Object) ([]corev1alpha1.ServiceDependency, error) {
+	value, ok := obj.GetAnnotations()[DependsOnAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var deps []corev1alpha1.ServiceDependency
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		dep, err := parseDependency(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s annotation entry %q: %w", DependsOnAnnotation, entry, err)
+		}
+		deps = append(deps, dep)
+	}
+	return deps, nil
+}
+
+// parseDependency parses one entry of the DependsOnAnnotation. An entry is either
+// <name>:<port>, probed over TCP, or an http or https URL, probed with an HTTP(S)
+// request to its path. A name without dots or colons is a Service in the workload's
+// namespace and may use a named port; anything else must be a DNS subdomain or an
+// IP address and is an external host.
+func parseDependency(entry string) (corev1alpha1.ServiceDependency, error) {
+	var dep corev1alpha1.ServiceDependency
+	var name, port string
+	if strings.Contains(entry, "://") {
+		u, err := url.Parse(entry)
+		if err != nil {
+			return dep, err
+		}
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "https":
+			dep.HTTPScheme = u.Scheme
+			port = "443"
+		default:
+			return dep, fmt.Errorf("unsupported scheme %q, expected http or https", u.Scheme)
+		}
+		if u.Port() != "" {
+			port = u.Port()
+		}
+		name = u.Hostname()
+		dep.HTTPPath = u.EscapedPath()
+		if dep.HTTPPath == "" {
+			dep.HTTPPath = "/"
+		}
+		if u.RawQuery != "" {
+			dep.HTTPPath += "?" + u.RawQuery
+		}
+	} else {
+		var err error
+		if name, port, err = net.SplitHostPort(entry); err != nil {
+			return dep, errors.New("expected <name>:<port> or an http(s) URL")
+		}
+	}
+	if name == "" {
+		return dep, errors.New("missing service or host name")
+	}
+
+	if strings.ContainsAny(name, ".:") {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 && net.ParseIP(name) == nil {
+			return dep, fmt.Errorf("invalid host %q: %s", name, strings.Join(errs, "; "))
+		}
+		dep.Host = name
+	} else {
+		if errs := validation.IsDNS1123Label(name); len(errs) > 0 {
+			return dep, fmt.Errorf("invalid service name %q: %s", name, strings.Join(errs, "; "))
+		}
+		dep.Service = name
+	}
+
+	n, err := strconv.ParseInt(port, 10, 32)
+	switch {
+	case err == nil && n >= 1 && n <= 65535:
+		dep.Port = int32(n)
+	case err == nil:
+		return dep, fmt.Errorf("port %d is out of range", n)
+	case dep.Service != "" && len(validation.IsValidPortName(port)) == 0:
+		dep.PortName = port
+	default:
+		return dep, fmt.Errorf("invalid port %q", port)
+	}
+	return dep, nil
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+// withMethod returns dep with httpMethod set, which switches the init container to curl.
+func withMethod(dep corev1alpha1.ServiceDependency, method string) corev1alpha1.ServiceDependency {
+	dep.HTTPMethod = method
+	return dep
+}
+
+var _ = Describe("DependsOnAnnotation", func() {
+	annotated := func(value string) *appsv1.Deployment {
+		return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:        "orders",
+			Namespace:   "default",
+			Annotations: map[string]string{DependsOnAnnotation: value},
+		}}
+	}
+
+	It("should return nothing when the annotation is not set", func() {
+		deps, err := annotationDependencies(&appsv1.Deployment{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deps).To(BeEmpty())
+	})
+
+	DescribeTable("should parse each entry into a dependency",
+		func(entry string, expected corev1alpha1.ServiceDependency) {
+			deps, err := annotationDependencies(annotated(entry))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deps).To(Equal([]corev1alpha1.ServiceDependency{expected}))
+		},
+		Entry("a Service and port", "postgres:5432",
+			corev1alpha1.ServiceDependency{Service: "postgres", Port: 5432}),
+		Entry("a Service and named port", "api:http",
+			corev1alpha1.ServiceDependency{Service: "api", PortName: "http"}),
+		Entry("an external host", "db.example.com:5432",
+			corev1alpha1.ServiceDependency{Host: "db.example.com", Port: 5432}),
+		Entry("an IPv6 address", "[fd00::10]:6379",
+			corev1alpha1.ServiceDependency{Host: "fd00::10", Port: 6379}),
+		Entry("an https URL with the default port", "https://auth/healthz",
+			corev1alpha1.ServiceDependency{Service: "auth", Port: 443, HTTPScheme: "https", HTTPPath: "/healthz"}),
+		Entry("an http URL with a port and query", "http://vault.example.com:8200/v1/sys/health?standbyok=true",
+			corev1alpha1.ServiceDependency{Host: "vault.example.com", Port: 8200, HTTPPath: "/v1/sys/health?standbyok=true"}),
+		Entry("an http URL without a path", "http://api",
+			corev1alpha1.ServiceDependency{Service: "api", Port: 80, HTTPPath: "/"}),
+	)
+
+	It("should keep shell syntax in a URL out of the init container's script", func() {
+		entry := `http://search/find?q=a&b=1'$(id)';reboot`
+		deps, err := annotationDependencies(annotated(entry))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deps).To(HaveLen(1))
+		Expect(deps[0].HTTPPath).To(Equal(`/find?q=a&b=1'$(id)';reboot`))
+
+		for _, dep := range []corev1alpha1.ServiceDependency{deps[0], withMethod(deps[0], "HEAD")} {
+			c := buildWaitContainer("wait-for-search", dep, "cluster.local")
+			script := c.Command[len(c.Command)-1]
+			Expect(script).NotTo(ContainSubstring("&b"))
+			Expect(script).NotTo(ContainSubstring("$(id)"))
+			Expect(script).To(ContainSubstring(`"$BOOTCHAIN_URL"`))
+			Expect(c.Env).To(ContainElement(corev1.EnvVar{Name: urlEnv, Value: "http://search:80" + deps[0].HTTPPath}))
+		}
+	})
+
+	It("should parse a comma-separated list in order and ignore empty entries", func() {
+		deps, err := annotationDependencies(annotated(" postgres:5432, ,https://auth/healthz,"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(deps).To(HaveLen(2))
+		Expect(deps[0].Service).To(Equal("postgres"))
+		Expect(deps[1].Service).To(Equal("auth"))
+	})
+
+	DescribeTable("should reject invalid entries",
+		func(entry, message string) {
+			_, err := annotationDependencies(annotated(entry))
+			Expect(err).To(MatchError(ContainSubstring(message)))
+			Expect(err).To(MatchError(ContainSubstring(DependsOnAnnotation)))
+		},
+		Entry("a missing port", "postgres", "expected <name>:<port> or an http(s) URL"),
+		Entry("an unsupported scheme", "grpc://ledger:9090", `unsupported scheme "grpc"`),
+		Entry("a port out of range", "postgres:70000", "port 70000 is out of range"),
+		Entry("a named port on an external host", "db.example.com:postgres", `invalid port "postgres"`),
+		Entry("an invalid Service name", "Postgres_DB:5432", `invalid service name "Postgres_DB"`),
+		Entry("a missing name", ":5432", "missing service or host name"),
+		Entry("an invalid host", "db_1.example.com:5432", `invalid host "db_1.example.com"`),
+		Entry("shell syntax in a URL's host", "http://a'b.example.com/healthz", `invalid host "a'b.example.com"`),
+	)
+})
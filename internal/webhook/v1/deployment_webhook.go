@@ -1,222 +0,0 @@
-/*
-Copyright 2026.
-
-Licensed under the Apache License, Version 2.0 (the "License");
-you may not use this file except in compliance with the License.
-You may obtain a copy of the License at
-
-    http://www.apache.org/licenses/LICENSE-2.0
-
-Unless required by applicable law or agreed to in writing, software
-distributed under the License is distributed on an "AS IS" BASIS,
-WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
-See the License for the specific language governing permissions and
-limitations under the License.
-*/
-
-package v1
-
-import (
-	"context"
-	"fmt"
-	"strings"
-
-	appsv1 "k8s.io/api/apps/v1"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client"
-	logf "sigs.k8s.io/controller-runtime/pkg/log"
-
-	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
-)
-
-var deploymentlog = logf.Log.WithName("deployment-webhook")
-
-// +kubebuilder:webhook:path=/mutate-apps-v1-deployment,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=deployments,verbs=create;update,versions=v1,name=mdeployment-v1.kb.io,admissionReviewVersions=v1
-
-// DeploymentCustomDefaulter injects init containers into Deployments based on
-// a BootDependency resource with the same name in the same namespace.
-type DeploymentCustomDefaulter struct {
-	Client client.Client
-}
-
-// SetupDeploymentWebhookWithManager registers the webhook for Deployment in the manager.
-func SetupDeploymentWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr, &appsv1.Deployment{}).
-		WithDefaulter(&DeploymentCustomDefaulter{Client: mgr.GetClient()}).
-		Complete()
-}
-
-// Default implements webhook.CustomDefaulter.
-func (d *DeploymentCustomDefaulter) Default(ctx context.Context, obj *appsv1.Deployment) error {
-	log := deploymentlog.WithValues("deployment", obj.Name, "namespace", obj.Namespace)
-
-	var bd corev1alpha1.BootDependency
-	err := d.Client.Get(ctx, types.NamespacedName{
-		Name:      obj.Name,
-		Namespace: obj.Namespace,
-	}, &bd)
-	if err != nil {
-		if client.IgnoreNotFound(err) == nil {
-			// No BootDependency for this Deployment — nothing to inject.
-			return nil
-		}
-		return fmt.Errorf("failed to get BootDependency %s/%s: %w", obj.Namespace, obj.Name, err)
-	}
-
-	log.Info("BootDependency found, injecting init containers", "dependencies", len(bd.Spec.DependsOn))
-
-	obj.Spec.Template.Spec.InitContainers = injectInitContainers(
-		obj.Spec.Template.Spec.InitContainers,
-		bd.Spec.DependsOn,
-	)
-
-	return nil
-}
-
-// injectInitContainers merges the required wait-for init containers into the
-// existing list, skipping any that are already present (idempotent).
-func injectInitContainers(existing []corev1.Container, deps []corev1alpha1.ServiceDependency) []corev1.Container {
-	existingNames := make(map[string]struct{}, len(existing))
-	for _, c := range existing {
-		existingNames[c.Name] = struct{}{}
-	}
-
-	result := make([]corev1.Container, 0, len(existing)+len(deps))
-
-	// Prepend the wait-for containers so they run before any user-defined init containers.
-	for _, dep := range deps {
-		name := fmt.Sprintf("wait-for-%s", depTarget(dep))
-		if _, ok := existingNames[name]; ok {
-			// Already injected — skip to stay idempotent.
-			continue
-		}
-		result = append(result, buildWaitContainer(name, dep))
-	}
-
-	result = append(result, existing...)
-	return result
-}
-
-// depTarget returns the hostname to connect to for a dependency.
-// For in-cluster services it returns the service name (resolved via cluster DNS);
-// for external deps it returns the host directly.
-func depTarget(dep corev1alpha1.ServiceDependency) string {
-	if dep.Host != "" {
-		return dep.Host
-	}
-	return dep.Service
-}
-
-// needsCurl reports whether any advanced HTTP fields are set that wget cannot handle.
-// wget --spider cannot use custom methods, send custom headers, or check specific status codes.
-func needsCurl(dep corev1alpha1.ServiceDependency) bool {
-	return dep.HTTPMethod != "" || len(dep.HTTPHeaders) > 0 || len(dep.HTTPExpectedStatuses) > 0
-}
-
-// buildCurlScript builds the sh -c script for the init container when advanced HTTP
-// fields (method, headers, or expected statuses) are in use.
-func buildCurlScript(url string, dep corev1alpha1.ServiceDependency, timeout string) string {
-	method := dep.HTTPMethod
-	if method == "" {
-		method = "GET"
-	}
-
-	var flagsBuilder strings.Builder
-	fmt.Fprintf(&flagsBuilder, "-s -o /dev/null -w '%%{http_code}' -X %s", method)
-
-	if dep.Insecure {
-		flagsBuilder.WriteString(" -k")
-	}
-
-	for _, h := range dep.HTTPHeaders {
-		// Shell-escape single quotes in the header value.
-		safeVal := strings.ReplaceAll(h.Value, "'", `'\''`)
-		fmt.Fprintf(&flagsBuilder, " --header '%s: %s'", h.Name, safeVal)
-	}
-
-	curlFlags := flagsBuilder.String()
-
-	// Build the condition that decides whether the status code is acceptable.
-	var checkExpr string
-	if len(dep.HTTPExpectedStatuses) == 0 {
-		// Default: accept any 2xx.
-		checkExpr = `[ "$STATUS" -ge 200 ] && [ "$STATUS" -lt 300 ]`
-	} else {
-		codes := make([]string, len(dep.HTTPExpectedStatuses))
-		for i, c := range dep.HTTPExpectedStatuses {
-			codes[i] = fmt.Sprintf("%d", c)
-		}
-		pattern := strings.Join(codes, "|")
-		checkExpr = fmt.Sprintf(`case "$STATUS" in %s) true ;; *) false ;; esac`, pattern)
-	}
-
-	return fmt.Sprintf(
-		"echo 'Waiting for %s...'; "+
-			"timeout %s sh -c '"+
-			`until STATUS=$(curl %s %s) && %s; `+
-			"do sleep 1; done"+
-			"' || { echo 'Timed out waiting for %s'; exit 1; }; "+
-			"echo '%s is ready'",
-		url, timeout, curlFlags, url, checkExpr, url, url,
-	)
-}
-
-// buildWaitContainer creates a minimal-tools init container that polls the given
-// host:port until it is reachable. When httpPath is set, an HTTP(S) probe is used
-// instead of a raw TCP check. Uses curl when advanced HTTP fields are set; wget otherwise.
-func buildWaitContainer(name string, dep corev1alpha1.ServiceDependency) corev1.Container {
-	timeout := dep.Timeout
-	if timeout == "" {
-		timeout = "60s"
-	}
-
-	target := depTarget(dep)
-
-	var script string
-	if dep.HTTPPath != "" {
-		scheme := dep.HTTPScheme
-		if scheme == "" {
-			scheme = "http"
-		}
-		url := fmt.Sprintf("%s://%s:%d%s", scheme, target, dep.Port, dep.HTTPPath)
-
-		if needsCurl(dep) {
-			script = buildCurlScript(url, dep, timeout)
-		} else {
-			// Default path: wget --spider exits 0 on any 2xx/3xx response.
-			// --no-check-certificate skips TLS verification for self-signed certs.
-			wgetFlags := "-q --spider"
-			if dep.Insecure {
-				wgetFlags += " --no-check-certificate"
-			}
-			script = fmt.Sprintf(
-				"echo 'Waiting for %s...'; "+
-					"timeout %s sh -c 'until wget %s %s; do sleep 1; done'"+
-					" || { echo 'Timed out waiting for %s'; exit 1; }; "+
-					"echo '%s is ready'",
-				url, timeout, wgetFlags, url, url, url,
-			)
-		}
-	} else {
-		script = fmt.Sprintf(
-			"echo 'Waiting for %s:%d...'; "+
-				"timeout %s sh -c 'until nc -z %s %d; do sleep 1; done'"+
-				" || { echo 'Timed out waiting for %s:%d'; exit 1; }; "+
-				"echo '%s:%d is ready'",
-			target, dep.Port,
-			timeout,
-			target, dep.Port,
-			target, dep.Port,
-			target, dep.Port,
-		)
-	}
-
-	return corev1.Container{
-		Name:            name,
-		Image:           "ghcr.io/user-cube/bootchain-operator/minimal-tools:latest",
-		ImagePullPolicy: corev1.PullIfNotPresent,
-		Command:         []string{"sh", "-c", script},
-	}
-}
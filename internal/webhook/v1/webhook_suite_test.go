@@ -41,6 +41,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -81,7 +82,7 @@ var _ = BeforeSuite(func() {
 		ErrorIfCRDPathMissing: false,
 
 		WebhookInstallOptions: envtest.WebhookInstallOptions{
-			Paths: []string{filepath.Join("..", "..", "..", "config", "webhook")},
+			Paths: []string{filepath.Join("..", "..", "..", "config", "webhook", "manifests.yaml")},
 		},
 	}
 
@@ -113,7 +114,7 @@ var _ = BeforeSuite(func() {
 	})
 	Expect(err).NotTo(HaveOccurred())
 
-	err = SetupDeploymentWebhookWithManager(mgr)
+	err = SetupWorkloadWebhooksWithManager(mgr, probe.Proxy{}, probe.DefaultClusterDomain)
 	Expect(err).NotTo(HaveOccurred())
 
 	// +kubebuilder:scaffold:webhook
@@ -0,0 +1,678 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
+)
+
+var workloadlog = logf.Log.WithName("workload-webhook")
+
+// minimalToolsImage is the image used by every injected wait-for-* init container.
+// It bundles nc, wget, curl and the bootchain-probe binary.
+const minimalToolsImage = "ghcr.io/user-cube/bootchain-operator/minimal-tools:latest"
+
+// +kubebuilder:webhook:path=/mutate-apps-v1-deployment,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=deployments,verbs=create;update,versions=v1,name=mdeployment-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-apps-v1-statefulset,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=statefulsets,verbs=create;update,versions=v1,name=mstatefulset-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-apps-v1-daemonset,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps,resources=daemonsets,verbs=create;update,versions=v1,name=mdaemonset-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-apps-v1-replicaset,mutating=true,failurePolicy=ignore,sideEffects=None,groups=apps,resources=replicasets,verbs=create;update,versions=v1,name=mreplicaset-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-batch-v1-job,mutating=true,failurePolicy=fail,sideEffects=None,groups=batch,resources=jobs,verbs=create,versions=v1,name=mjob-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate-batch-v1-cronjob,mutating=true,failurePolicy=fail,sideEffects=None,groups=batch,resources=cronjobs,verbs=create;update,versions=v1,name=mcronjob-v1.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/mutate--v1-pod,mutating=true,failurePolicy=ignore,sideEffects=None,groups="",resources=pods,verbs=create,versions=v1,name=mpod-v1.kb.io,admissionReviewVersions=v1
+
+// The ReplicaSet and Pod webhooks ignore failures: their templates normally come from
+// a Deployment or another workload that was already mutated, and failing closed would
+// block every ReplicaSet and Pod in the cluster, including the operator's own, while
+// the operator is down.
+
+// TargetIndexKey is the BootDependency field index the webhook looks up the
+// BootDependencies that target a workload with. See TargetIndexValues.
+const TargetIndexKey = "spec.target"
+
+// selectorTarget is the name part of the index value of a BootDependency that targets
+// workloads by label selector. It is not a valid object name.
+const selectorTarget = "*"
+
+// TargetIndexValues indexes a BootDependency by the workloads it targets, as
+// "<kind>/<name>": its targetRef, or the workload of kind targetKind with the same name
+// as the BootDependency. A BootDependency with a selector is indexed as "<kind>/*".
+func TargetIndexValues(obj client.Object) []string {
+	bd, ok := obj.(*corev1alpha1.BootDependency)
+	if !ok {
+		return nil
+	}
+	name := bd.Name
+	switch {
+	case bd.Spec.TargetRef != nil:
+		name = bd.Spec.TargetRef.Name
+	case bd.Spec.Selector != nil:
+		name = selectorTarget
+	}
+	return []string{targetIndexValue(bd.Spec.TargetKindOrDefault(), name)}
+}
+
+// targetIndexValue returns the TargetIndexKey value of a workload.
+func targetIndexValue(kind, name string) string {
+	return kind + "/" + name
+}
+
+// WorkloadCustomDefaulter injects init containers into the pod template of a
+// Deployment, StatefulSet, DaemonSet, ReplicaSet, Job or CronJob, or into a standalone
+// Pod, based on the BootDependencies in the same namespace that target it: by
+// targetRef, by selector, or by having the same name and the object's kind as targetKind.
+// The dependencies of matching ClusterBootDependencies are merged after them, and
+// those declared inline with the DependsOnAnnotation before them.
+type WorkloadCustomDefaulter struct {
+	// Client reads BootDependencies through the TargetIndexKey field index, and
+	// ClusterBootDependencies and Namespaces.
+	Client client.Client
+	// Proxy is the operator-wide proxy baked into the init containers of host dependencies.
+	Proxy probe.Proxy
+	// ClusterDomain is the DNS domain of the cluster used to build the hostnames of
+	// Services in other namespaces. Defaults to cluster.local.
+	ClusterDomain string
+}
+
+// SetupWorkloadWebhooksWithManager registers the TargetIndexKey field index and the
+// webhooks for every supported workload kind in the manager.
+func SetupWorkloadWebhooksWithManager(mgr ctrl.Manager, proxy probe.Proxy, clusterDomain string) error {
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1alpha1.BootDependency{},
+		TargetIndexKey, TargetIndexValues); err != nil {
+		return err
+	}
+	d := &WorkloadCustomDefaulter{Client: mgr.GetClient(), Proxy: proxy, ClusterDomain: clusterDomain}
+	for _, err := range []error{
+		setupWebhook(mgr, &appsv1.Deployment{}, d),
+		setupWebhook(mgr, &appsv1.StatefulSet{}, d),
+		setupWebhook(mgr, &appsv1.DaemonSet{}, d),
+		setupWebhook(mgr, &appsv1.ReplicaSet{}, d),
+		setupWebhook(mgr, &batchv1.Job{}, d),
+		setupWebhook(mgr, &batchv1.CronJob{}, d),
+		setupWebhook(mgr, &corev1.Pod{}, d),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setupWebhook registers d as the defaulting webhook for the kind of obj.
+func setupWebhook[T client.Object](mgr ctrl.Manager, obj T, d *WorkloadCustomDefaulter) error {
+	return ctrl.NewWebhookManagedBy(mgr, obj).WithDefaulter(typedDefaulter[T]{d}).Complete()
+}
+
+// typedDefaulter adapts WorkloadCustomDefaulter to the typed admission.Defaulter of one kind.
+type typedDefaulter[T client.Object] struct {
+	defaulter *WorkloadCustomDefaulter
+}
+
+// Default implements admission.Defaulter.
+func (t typedDefaulter[T]) Default(ctx context.Context, obj T) error {
+	return t.defaulter.Default(ctx, obj)
+}
+
+// podTemplate returns the kind of obj and the pod spec the init containers are injected
+// into: the pod template of a workload, the jobTemplate's pod template of a CronJob or
+// the spec of a Pod.
+func podTemplate(obj client.Object) (string, *corev1.PodSpec, error) {
+	switch o := obj.(type) {
+	case *appsv1.Deployment:
+		return "Deployment", &o.Spec.Template.Spec, nil
+	case *appsv1.StatefulSet:
+		return "StatefulSet", &o.Spec.Template.Spec, nil
+	case *appsv1.DaemonSet:
+		return "DaemonSet", &o.Spec.Template.Spec, nil
+	case *appsv1.ReplicaSet:
+		return "ReplicaSet", &o.Spec.Template.Spec, nil
+	case *batchv1.Job:
+		return "Job", &o.Spec.Template.Spec, nil
+	case *batchv1.CronJob:
+		return "CronJob", &o.Spec.JobTemplate.Spec.Template.Spec, nil
+	case *corev1.Pod:
+		return "Pod", &o.Spec, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported kind %T", obj)
+	}
+}
+
+// Default injects the init containers of the dependencies declared by obj's
+// DependsOnAnnotation and of every BootDependency and ClusterBootDependency that
+// targets obj.
+func (d *WorkloadCustomDefaulter) Default(ctx context.Context, obj client.Object) error {
+	kind, spec, err := podTemplate(obj)
+	if err != nil {
+		return err
+	}
+	log := workloadlog.WithValues("kind", kind, "name", obj.GetName(), "namespace", obj.GetNamespace())
+
+	annotated, err := annotationDependencies(obj)
+	if err != nil {
+		return err
+	}
+	bds, err := d.targetingBootDependencies(ctx, kind, obj)
+	if err != nil {
+		return err
+	}
+	cbds, err := d.targetingClusterBootDependencies(ctx, kind, obj)
+	if err != nil {
+		return err
+	}
+	if len(annotated) == 0 && len(bds) == 0 && len(cbds) == 0 {
+		// No BootDependency for this workload — nothing to inject.
+		return nil
+	}
+
+	// The dependencies declared on the workload itself come first, then the namespaced
+	// ones, so that when two of them have the same init container name,
+	// injectInitContainers keeps the most specific one.
+	deps := annotated
+	names := make([]string, 0, len(bds))
+	for _, bd := range bds {
+		deps = append(deps, bd.Spec.DependsOn...)
+		names = append(names, bd.Name)
+	}
+	clusterNames := make([]string, 0, len(cbds))
+	for _, cbd := range cbds {
+		deps = append(deps, cbd.Spec.DependsOn...)
+		clusterNames = append(clusterNames, cbd.Name)
+	}
+	log.Info("BootDependency found, injecting init containers", "annotated", len(annotated),
+		"bootDependencies", names, "clusterBootDependencies", clusterNames, "dependencies", len(deps))
+
+	deps = d.resolvePortNames(ctx, obj.GetNamespace(), deps)
+	spec.InitContainers = injectInitContainers(
+		spec.InitContainers,
+		d.applyServerNames(obj.GetNamespace(), d.applyProxy(deps)),
+		d.ClusterDomain,
+	)
+
+	return nil
+}
+
+// targetingBootDependencies returns the BootDependencies in obj's namespace that target
+// it, sorted by name: the one whose targetRef names it, or with its name when it has
+// none, and those whose selector matches its labels. Objects created with generateName,
+// such as the Pods of a ReplicaSet, have no name yet and are only matched by selector.
+func (d *WorkloadCustomDefaulter) targetingBootDependencies(ctx context.Context, kind string, obj client.Object) ([]corev1alpha1.BootDependency, error) {
+	var matched []corev1alpha1.BootDependency
+	if obj.GetName() != "" {
+		var byName corev1alpha1.BootDependencyList
+		if err := d.Client.List(ctx, &byName, client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{TargetIndexKey: targetIndexValue(kind, obj.GetName())}); err != nil {
+			return nil, fmt.Errorf("failed to list BootDependencies for %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+		}
+		matched = append(matched, byName.Items...)
+	}
+
+	var bySelector corev1alpha1.BootDependencyList
+	if err := d.Client.List(ctx, &bySelector, client.InNamespace(obj.GetNamespace()),
+		client.MatchingFields{TargetIndexKey: targetIndexValue(kind, selectorTarget)}); err != nil {
+		return nil, fmt.Errorf("failed to list BootDependencies for %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	for _, bd := range bySelector.Items {
+		selector, err := metav1.LabelSelectorAsSelector(bd.Spec.Selector)
+		if err != nil {
+			workloadlog.Info("Ignoring BootDependency with an invalid selector",
+				"bootDependency", bd.Name, "namespace", bd.Namespace, "error", err)
+			continue
+		}
+		if selector.Matches(labels.Set(obj.GetLabels())) {
+			matched = append(matched, bd)
+		}
+	}
+
+	slices.SortFunc(matched, func(a, b corev1alpha1.BootDependency) int { return strings.Compare(a.Name, b.Name) })
+	return matched, nil
+}
+
+// targetingClusterBootDependencies returns the ClusterBootDependencies that target obj,
+// sorted by name: those whose targetKind is kind, whose namespaceSelector matches the
+// labels of obj's namespace and whose selector matches obj's labels.
+func (d *WorkloadCustomDefaulter) targetingClusterBootDependencies(ctx context.Context, kind string, obj client.Object) ([]corev1alpha1.ClusterBootDependency, error) {
+	var list corev1alpha1.ClusterBootDependencyList
+	if err := d.Client.List(ctx, &list); err != nil {
+		return nil, fmt.Errorf("failed to list ClusterBootDependencies for %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
+	}
+	var ns *corev1.Namespace
+	var matched []corev1alpha1.ClusterBootDependency
+	for _, cbd := range list.Items {
+		if cbd.Spec.TargetKindOrDefault() != kind {
+			continue
+		}
+		if ns == nil {
+			ns = &corev1.Namespace{}
+			if err := d.Client.Get(ctx, types.NamespacedName{Name: obj.GetNamespace()}, ns); err != nil {
+				return nil, fmt.Errorf("failed to get Namespace %s: %w", obj.GetNamespace(), err)
+			}
+		}
+		if !selectorMatches(cbd.Spec.NamespaceSelector, ns.Labels) || !selectorMatches(cbd.Spec.Selector, obj.GetLabels()) {
+			continue
+		}
+		matched = append(matched, cbd)
+	}
+
+	slices.SortFunc(matched, func(a, b corev1alpha1.ClusterBootDependency) int { return strings.Compare(a.Name, b.Name) })
+	return matched, nil
+}
+
+// selectorMatches reports whether selector matches set. A nil selector matches
+// everything; an invalid one matches nothing.
+func selectorMatches(selector *metav1.LabelSelector, set map[string]string) bool {
+	if selector == nil {
+		return true
+	}
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		workloadlog.Info("Ignoring ClusterBootDependency with an invalid selector", "error", err)
+		return false
+	}
+	return sel.Matches(labels.Set(set))
+}
+
+// resolvePortNames returns a copy of deps in which named Service ports are replaced by
+// their numbers, so that the shell checks can dial them. Services are read from the
+// dependency's namespace, or from namespace when it has none. A port whose Service
+// cannot be read yet is left named and resolved by bootchain-probe when the pod starts.
+func (d *WorkloadCustomDefaulter) resolvePortNames(ctx context.Context, namespace string, deps []corev1alpha1.ServiceDependency) []corev1alpha1.ServiceDependency {
+	resolved := make([]corev1alpha1.ServiceDependency, len(deps))
+	copy(resolved, deps)
+	for i := range resolved {
+		dep := &resolved[i]
+		if dep.PortName == "" || dep.Port != 0 {
+			continue
+		}
+		svcNamespace := namespace
+		if dep.Namespace != "" {
+			svcNamespace = dep.Namespace
+		}
+		var svc corev1.Service
+		if err := d.Client.Get(ctx, types.NamespacedName{Namespace: svcNamespace, Name: dep.Service}, &svc); err != nil {
+			workloadlog.Info("Deferring named port resolution to the init container",
+				"service", dep.Service, "portName", dep.PortName, "error", err)
+			continue
+		}
+		if port, ok := probe.NamedPort(&svc, dep.PortName); ok {
+			dep.Port = port
+		}
+	}
+	return resolved
+}
+
+// applyProxy resolves the effective proxy of every dependency in place, so that the
+// init container uses the same proxy as the controller.
+func (d *WorkloadCustomDefaulter) applyProxy(deps []corev1alpha1.ServiceDependency) []corev1alpha1.ServiceDependency {
+	for i, dep := range deps {
+		deps[i] = d.Proxy.ApplyToInitContainer(dep.Host, dep)
+	}
+	return deps
+}
+
+// applyServerNames sets, in place, the serverName of every TLS probe that has none to
+// the host the controller verifies, the FQDN of a Service in namespace. bootchain-probe
+// connects to the shorter target name and would otherwise verify that one instead.
+func (d *WorkloadCustomDefaulter) applyServerNames(namespace string, deps []corev1alpha1.ServiceDependency) []corev1alpha1.ServiceDependency {
+	for i, dep := range deps {
+		if dep.TLS == nil || dep.TLS.ServerName != "" {
+			continue
+		}
+		tls := *dep.TLS
+		tls.ServerName = probe.DependencyHost(dep, namespace, d.ClusterDomain)
+		deps[i].TLS = &tls
+	}
+	return deps
+}
+
+// injectInitContainers merges the required wait-for init containers into the
+// existing list, skipping any that are already present (idempotent).
+func injectInitContainers(existing []corev1.Container, deps []corev1alpha1.ServiceDependency, clusterDomain string) []corev1.Container {
+	existingNames := make(map[string]struct{}, len(existing))
+	for _, c := range existing {
+		existingNames[c.Name] = struct{}{}
+	}
+
+	result := make([]corev1.Container, 0, len(existing)+len(deps))
+
+	// Prepend the wait-for containers so they run before any user-defined init containers.
+	for _, dep := range deps {
+		name := containerName(dep)
+		if _, ok := existingNames[name]; ok {
+			// Already injected — skip to stay idempotent.
+			continue
+		}
+		result = append(result, buildWaitContainer(name, dep, clusterDomain))
+		existingNames[name] = struct{}{}
+	}
+
+	result = append(result, existing...)
+	return result
+}
+
+// containerName returns the name of the init container for a dependency. A Service in
+// another namespace is named wait-for-<service>-<namespace>, without the cluster domain,
+// so that changing the domain does not inject a second container on the next update.
+func containerName(dep corev1alpha1.ServiceDependency) string {
+	target := depTarget(dep, "")
+	if dep.Service != "" && dep.Namespace != "" {
+		target = dep.Service + "-" + dep.Namespace
+	}
+	// Workload, Job and resource targets such as job/app=migrate, hostnames and IPv6
+	// addresses are not valid container names, which are DNS-1123 labels: upper-case
+	// letters are lowered and anything else but letters and digits becomes a dash.
+	name := "wait-for-" + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		default:
+			return '-'
+		}
+	}, target)
+	if len(name) > validation.DNS1123LabelMaxLength {
+		// Keep truncated names of different targets apart with a hash of the target.
+		sum := sha256.Sum256([]byte(target))
+		suffix := "-" + hex.EncodeToString(sum[:4])
+		name = strings.TrimRight(name[:validation.DNS1123LabelMaxLength-len(suffix)], "-") + suffix
+	}
+	return strings.TrimRight(name, "-")
+}
+
+// depTarget returns the hostname to connect to for a dependency.
+// For in-cluster services it returns the service name (resolved via cluster DNS), or
+// the FQDN <service>.<namespace>.svc.<clusterDomain> for a Service in another namespace;
+// for external deps it returns the host directly. Workloads, Jobs and other resources
+// are not dialled and are identified as kind/name, e.g. deployment/postgres, or
+// job/selector for Jobs matched by label. An empty clusterDomain means cluster.local.
+func depTarget(dep corev1alpha1.ServiceDependency, clusterDomain string) string {
+	if dep.WorkloadRef != nil {
+		return strings.ToLower(dep.WorkloadRef.Kind) + "/" + dep.WorkloadRef.Name
+	}
+	if dep.JobRef != nil {
+		if dep.JobRef.Name != "" {
+			return "job/" + dep.JobRef.Name
+		}
+		return "job/" + labels.SelectorFromSet(dep.JobRef.Selector).String()
+	}
+	if dep.ResourceRef != nil {
+		return strings.ToLower(dep.ResourceRef.Kind) + "/" + dep.ResourceRef.Name
+	}
+	if dep.Host != "" {
+		return dep.Host
+	}
+	if dep.Namespace != "" {
+		if clusterDomain == "" {
+			clusterDomain = probe.DefaultClusterDomain
+		}
+		return probe.ServiceHost(dep.Service, dep.Namespace, clusterDomain)
+	}
+	return dep.Service
+}
+
+// urlEnv is the environment variable through which the wget and curl scripts receive
+// the URL to probe. The scripts only expand it inside double quotes, so the path and
+// query of the URL cannot inject shell syntax.
+const urlEnv = "BOOTCHAIN_URL"
+
+// needsCurl reports whether any advanced HTTP fields are set that wget cannot handle.
+// wget --spider cannot use custom methods, send custom headers, or check specific status codes.
+func needsCurl(dep corev1alpha1.ServiceDependency) bool {
+	return dep.HTTPMethod != "" || len(dep.HTTPHeaders) > 0 || len(dep.HTTPExpectedStatuses) > 0
+}
+
+// buildCurlScript builds the sh -c script for the init container when advanced HTTP
+// fields (method, headers, or expected statuses) are in use.
+func buildCurlScript(url string, dep corev1alpha1.ServiceDependency, timeout string) string {
+	method := dep.HTTPMethod
+	if method == "" {
+		method = "GET"
+	}
+
+	var flagsBuilder strings.Builder
+	fmt.Fprintf(&flagsBuilder, "-s -o /dev/null -w '%%{http_code}' -X %s", method)
+
+	if dep.Insecure {
+		flagsBuilder.WriteString(" -k")
+	}
+	if strings.Contains(url, "[") {
+		// Without --globoff curl reads the brackets of an IPv6 address as a URL range.
+		flagsBuilder.WriteString(" -g")
+	}
+
+	for _, h := range dep.HTTPHeaders {
+		if env := headerEnvName(h); env != "" {
+			// The inner shell expands the value from the environment, so it never
+			// appears in the command.
+			fmt.Fprintf(&flagsBuilder, ` --header "%s: ${%s}"`, h.Name, env)
+			continue
+		}
+		// Shell-escape single quotes in the header value.
+		safeVal := strings.ReplaceAll(h.Value, "'", `'\''`)
+		fmt.Fprintf(&flagsBuilder, " --header '%s: %s'", h.Name, safeVal)
+	}
+
+	curlFlags := flagsBuilder.String()
+
+	// Build the condition that decides whether the status code is acceptable.
+	var checkExpr string
+	if len(dep.HTTPExpectedStatuses) == 0 {
+		// Default: accept any 2xx.
+		checkExpr = `[ "$STATUS" -ge 200 ] && [ "$STATUS" -lt 300 ]`
+	} else {
+		codes := make([]string, len(dep.HTTPExpectedStatuses))
+		for i, c := range dep.HTTPExpectedStatuses {
+			codes[i] = fmt.Sprintf("%d", c)
+		}
+		pattern := strings.Join(codes, "|")
+		checkExpr = fmt.Sprintf(`case "$STATUS" in %s) true ;; *) false ;; esac`, pattern)
+	}
+
+	return fmt.Sprintf(
+		`echo "Waiting for $%[1]s..."; `+
+			"timeout %[2]s sh -c '"+
+			`until STATUS=$(curl %[3]s "$%[1]s") && %[4]s; `+
+			"do sleep 1; done"+
+			`' || { echo "Timed out waiting for $%[1]s"; exit 1; }; `+
+			`echo "$%[1]s is ready"`,
+		urlEnv, timeout, curlFlags, checkExpr,
+	)
+}
+
+// headerEnvName returns the environment variable that carries the value of a header
+// read from a Secret or ConfigMap, or "" for a literal value.
+func headerEnvName(h corev1alpha1.HTTPHeader) string {
+	switch {
+	case h.ValueFrom == nil:
+		return ""
+	case h.ValueFrom.SecretKeyRef != nil:
+		return probe.SecretEnvName(h.ValueFrom.SecretKeyRef.Name, h.ValueFrom.SecretKeyRef.Key)
+	case h.ValueFrom.ConfigMapKeyRef != nil:
+		return probe.ConfigMapEnvName(h.ValueFrom.ConfigMapKeyRef.Name, h.ValueFrom.ConfigMapKeyRef.Key)
+	default:
+		return ""
+	}
+}
+
+// needsProbeBinary reports whether the dependency uses a protocol-level probe, an
+// httpExpression, a CA bundle or client certificate, a proxy, a dual-stack check, an
+// EndpointSlice check, a workloadRef, jobRef or resourceRef, or a named port that is
+// still unresolved, which the shell tools cannot perform and must be delegated to
+// bootchain-probe.
+func needsProbeBinary(dep corev1alpha1.ServiceDependency) bool {
+	return (dep.PortName != "" && dep.Port == 0) ||
+		dep.WorkloadRef != nil || dep.JobRef != nil || dep.ResourceRef != nil ||
+		dep.GRPC != nil || dep.Postgres != nil || dep.MySQL != nil || dep.Redis != nil ||
+		dep.Kafka != nil || dep.AMQP != nil || dep.MongoDB != nil || dep.DNS != nil ||
+		dep.TLS != nil || dep.Endpoints != nil || dep.HTTPExpression != "" ||
+		dep.CABundleRef != nil || dep.ClientCertSecretRef != nil || probe.ProxyURL(dep) != "" || dep.DualStack
+}
+
+// buildProbeBinaryContainer creates an init container that runs bootchain-probe in a
+// loop. The dependency is passed as JSON through the environment so that the binary
+// evaluates exactly the same spec as the controller. Secret and ConfigMap keys the
+// probe needs are mapped into the environment and never appear in the command.
+func buildProbeBinaryContainer(name string, dep corev1alpha1.ServiceDependency, target, timeout string) corev1.Container {
+	// DNS, EndpointSlice, workload, Job and resource probes have no port, so the dependency is named by its target alone.
+	endpoint := target
+	switch {
+	case dep.Port != 0:
+		endpoint = net.JoinHostPort(target, strconv.Itoa(int(dep.Port)))
+	case dep.PortName != "":
+		endpoint = net.JoinHostPort(target, dep.PortName)
+	}
+	script := fmt.Sprintf(
+		"echo 'Waiting for %s...'; "+
+			"timeout %s sh -c 'until bootchain-probe; do sleep 1; done'"+
+			" || { echo 'Timed out waiting for %s'; exit 1; }; "+
+			"echo '%s is ready'",
+		endpoint,
+		timeout,
+		endpoint,
+		endpoint,
+	)
+
+	env := []corev1.EnvVar{
+		{Name: probe.TargetEnv, Value: target},
+		{Name: probe.DependencyEnv, Value: probe.MarshalDependency(dep)},
+	}
+
+	return corev1.Container{
+		Name:            name,
+		Image:           minimalToolsImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", script},
+		Env:             append(env, refEnv(dep)...),
+	}
+}
+
+// refEnv maps every Secret and ConfigMap key the dependency reads into environment
+// variables sourced with secretKeyRef and configMapKeyRef.
+func refEnv(dep corev1alpha1.ServiceDependency) []corev1.EnvVar {
+	var env []corev1.EnvVar
+	for _, ref := range probe.SecretRefs(dep) {
+		env = append(env, corev1.EnvVar{
+			Name: probe.SecretEnvName(ref.Name, ref.Key),
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+					Key:                  ref.Key,
+				},
+			},
+		})
+	}
+	for _, ref := range probe.ConfigMapRefs(dep) {
+		env = append(env, corev1.EnvVar{
+			Name: probe.ConfigMapEnvName(ref.Name, ref.Key),
+			ValueFrom: &corev1.EnvVarSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: ref.Name},
+					Key:                  ref.Key,
+				},
+			},
+		})
+	}
+	return env
+}
+
+// buildWaitContainer creates a minimal-tools init container that polls the given
+// host:port until it is reachable. When httpPath is set, an HTTP(S) probe is used
+// instead of a raw TCP check. Uses curl when advanced HTTP fields are set; wget otherwise.
+// Protocol-level probes such as gRPC are delegated to the bootchain-probe binary.
+func buildWaitContainer(name string, dep corev1alpha1.ServiceDependency, clusterDomain string) corev1.Container {
+	timeout := dep.Timeout
+	if timeout == "" {
+		timeout = "60s"
+	}
+
+	target := depTarget(dep, clusterDomain)
+
+	if needsProbeBinary(dep) {
+		return buildProbeBinaryContainer(name, dep, target, timeout)
+	}
+
+	var script string
+	var env []corev1.EnvVar
+	if dep.HTTPPath != "" {
+		scheme := dep.HTTPScheme
+		if scheme == "" {
+			scheme = "http"
+		}
+		url := fmt.Sprintf("%s://%s%s", scheme, net.JoinHostPort(target, strconv.Itoa(int(dep.Port))), dep.HTTPPath)
+
+		if needsCurl(dep) {
+			script = buildCurlScript(url, dep, timeout)
+		} else {
+			// Default path: wget --spider exits 0 on any 2xx/3xx response.
+			// --no-check-certificate skips TLS verification for self-signed certs.
+			wgetFlags := "-q --spider"
+			if dep.Insecure {
+				wgetFlags += " --no-check-certificate"
+			}
+			script = fmt.Sprintf(
+				`echo "Waiting for $%[1]s..."; `+
+					`timeout %[2]s sh -c 'until wget %[3]s "$%[1]s"; do sleep 1; done'`+
+					` || { echo "Timed out waiting for $%[1]s"; exit 1; }; `+
+					`echo "$%[1]s is ready"`,
+				urlEnv, timeout, wgetFlags,
+			)
+		}
+		env = append(env, corev1.EnvVar{Name: urlEnv, Value: url})
+	} else {
+		// nc takes the host and port as separate arguments, so IPv6 addresses need no brackets.
+		endpoint := net.JoinHostPort(target, strconv.Itoa(int(dep.Port)))
+		script = fmt.Sprintf(
+			"echo 'Waiting for %s...'; "+
+				"timeout %s sh -c 'until nc -z %s %d; do sleep 1; done'"+
+				" || { echo 'Timed out waiting for %s'; exit 1; }; "+
+				"echo '%s is ready'",
+			endpoint,
+			timeout,
+			target, dep.Port,
+			endpoint,
+			endpoint,
+		)
+	}
+
+	return corev1.Container{
+		Name:            name,
+		Image:           minimalToolsImage,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         []string{"sh", "-c", script},
+		Env:             append(env, refEnv(dep)...),
+	}
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
+)
+
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// authorizeDependencies checks that the user who sent the admission request may read
+// the objects that deps make the operator read on their behalf, so that a dependency
+// cannot be used to reach objects through the operator's cluster-wide permissions. A
+// Service in another namespace must be readable there. The Secrets and ConfigMaps
+// whose values the probe sends to the dependency, such as credentials and header
+// values, must be readable in namespace. A resourceRef without a namespace is checked
+// in namespace, and all of these in all namespaces when namespace is empty, as for a
+// ClusterBootDependency.
+func authorizeDependencies(ctx context.Context, c client.Client, namespace string, deps []corev1alpha1.ServiceDependency) error {
+	for i, dep := range deps {
+		if attrs, ok := serviceAttributes(namespace, dep); ok {
+			path := field.NewPath("spec", "dependsOn").Index(i).Child("namespace")
+			if err := authorize(ctx, c, attrs, path); err != nil {
+				return err
+			}
+		}
+		for _, attrs := range keyAttributes(namespace, dep) {
+			if err := authorize(ctx, c, attrs, field.NewPath("spec", "dependsOn").Index(i)); err != nil {
+				return err
+			}
+		}
+		if dep.ResourceRef == nil {
+			continue
+		}
+		path := field.NewPath("spec", "dependsOn").Index(i).Child("resourceRef")
+		attrs, err := resourceAttributes(c, namespace, *dep.ResourceRef)
+		if err != nil {
+			return field.Invalid(path.Child("apiVersion"), dep.ResourceRef.APIVersion, err.Error())
+		}
+		if err := authorize(ctx, c, attrs, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resourceAttributes returns the attributes of a get of the object ref points at. A
+// kind that is not installed yet is mapped to its guessed resource, so that installing
+// its CRD later does not bypass the check.
+func resourceAttributes(c client.Client, namespace string, ref corev1alpha1.ResourceRef) (authorizationv1.ResourceAttributes, error) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return authorizationv1.ResourceAttributes{}, err
+	}
+	if ref.Namespace != "" {
+		namespace = ref.Namespace
+	}
+	gvk := gv.WithKind(ref.Kind)
+	resource, _ := meta.UnsafeGuessKindToResource(gvk)
+	mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+	switch {
+	case err == nil:
+		resource = mapping.Resource
+		if mapping.Scope.Name() == meta.RESTScopeNameRoot {
+			namespace = ""
+		}
+	case !meta.IsNoMatchError(err):
+		return authorizationv1.ResourceAttributes{}, err
+	}
+	return authorizationv1.ResourceAttributes{
+		Namespace: namespace,
+		Verb:      "get",
+		Group:     resource.Group,
+		Version:   resource.Version,
+		Resource:  resource.Resource,
+		Name:      ref.Name,
+	}, nil
+}
+
+// serviceAttributes returns the attributes of a get of the Service dep names in
+// another namespace, or false when dep does not leave namespace.
+func serviceAttributes(namespace string, dep corev1alpha1.ServiceDependency) (authorizationv1.ResourceAttributes, bool) {
+	if dep.Service == "" || dep.Namespace == "" || dep.Namespace == namespace {
+		return authorizationv1.ResourceAttributes{}, false
+	}
+	return authorizationv1.ResourceAttributes{
+		Namespace: dep.Namespace,
+		Verb:      "get",
+		Version:   "v1",
+		Resource:  "services",
+		Name:      dep.Service,
+	}, true
+}
+
+// keyAttributes returns the attributes of a get of every Secret and ConfigMap the
+// probe of dep reads in namespace, once per object.
+func keyAttributes(namespace string, dep corev1alpha1.ServiceDependency) []authorizationv1.ResourceAttributes {
+	var attrs []authorizationv1.ResourceAttributes
+	add := func(resource, name string) {
+		a := authorizationv1.ResourceAttributes{
+			Namespace: namespace,
+			Verb:      "get",
+			Version:   "v1",
+			Resource:  resource,
+			Name:      name,
+		}
+		if !slices.Contains(attrs, a) {
+			attrs = append(attrs, a)
+		}
+	}
+	for _, ref := range probe.SecretRefs(dep) {
+		add("secrets", ref.Name)
+	}
+	for _, ref := range probe.ConfigMapRefs(dep) {
+		add("configmaps", ref.Name)
+	}
+	return attrs
+}
+
+// authorize runs a SubjectAccessReview of attrs for the user who sent the admission
+// request in ctx and returns a Forbidden error for path when it is not allowed.
+func authorize(ctx context.Context, c client.Client, attrs authorizationv1.ResourceAttributes, path *field.Path) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot authorize %s: %w", path, err)
+	}
+	user := req.UserInfo
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attrs,
+			User:               user.Username,
+			UID:                user.UID,
+			Groups:             user.Groups,
+			Extra:              extra,
+		},
+	}
+	if err := c.Create(ctx, sar); err != nil {
+		return fmt.Errorf("failed to authorize %s: %w", path, err)
+	}
+	if !sar.Status.Allowed {
+		resource := attrs.Resource
+		if attrs.Group != "" {
+			resource += "." + attrs.Group
+		}
+		where := "cluster-wide"
+		if attrs.Namespace != "" {
+			where = fmt.Sprintf("in namespace %q", attrs.Namespace)
+		}
+		return field.Forbidden(path, fmt.Sprintf("user %q may not %s %s %q %s",
+			user.Username, attrs.Verb, resource, attrs.Name, where))
+	}
+	return nil
+}
@@ -19,8 +19,12 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -28,6 +32,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+	"github.com/user-cube/bootchain-operator/internal/probe"
 )
 
 var bootdependencylog = logf.Log.WithName("bootdependency-webhook")
@@ -61,29 +66,30 @@ func (v *BootDependencyCustomValidator) ValidateDelete(_ context.Context, _ *cor
 	return nil, nil
 }
 
-// validate checks for mutual exclusion of service/host fields and circular
-// dependencies in the BootDependency graph for the namespace.
+// validate checks for mutual exclusion of service/host/workloadRef/jobRef/resourceRef
+// fields, invalid httpExpression and resourceRef expressions, resourceRefs to objects
+// the requesting user may not read, and circular dependencies in the BootDependency
+// graph, which spans namespaces. Services and ports that do not
+// exist are reported as warnings.
 func (v *BootDependencyCustomValidator) validate(ctx context.Context, bd *corev1alpha1.BootDependency) (admission.Warnings, error) {
-	// Validate that exactly one of service or host is set for each dependency.
-	for i, dep := range bd.Spec.DependsOn {
-		hasService := dep.Service != ""
-		hasHost := dep.Host != ""
-		if hasService == hasHost {
-			return nil, field.Invalid(
-				field.NewPath("spec", "dependsOn").Index(i),
-				dep,
-				"exactly one of service or host must be specified",
-			)
-		}
+	if err := validateDependencies(bd.Spec.DependsOn); err != nil {
+		return nil, err
+	}
+	if err := authorizeDependencies(ctx, v.Client, bd.Namespace, bd.Spec.DependsOn); err != nil {
+		return nil, err
 	}
 
-	// Build a map of all BootDependency objects in the namespace, including the one being created/updated.
+	// Build a map of all BootDependency objects in the cluster, including the one being created/updated.
 	graph, err := v.buildGraph(ctx, bd)
 	if err != nil {
 		return nil, err
 	}
 
-	if cycle := detectCycle(bd.Name, graph); cycle != nil {
+	if cycle := detectCycle(nodeKey(bd.Namespace, bd.Name), graph); cycle != nil {
+		for i, node := range cycle {
+			// Only nodes in other namespaces are shown qualified.
+			cycle[i] = strings.TrimPrefix(node, bd.Namespace+"/")
+		}
 		path := strings.Join(cycle, " → ")
 		return nil, field.Invalid(
 			field.NewPath("spec", "dependsOn"),
@@ -92,71 +98,209 @@ func (v *BootDependencyCustomValidator) validate(ctx context.Context, bd *corev1
 		)
 	}
 
-	return nil, nil
+	var warnings admission.Warnings
+	for i, dep := range bd.Spec.DependsOn {
+		if warning := v.serviceWarning(ctx, bd.Namespace, i, dep); warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings, nil
 }
 
-// buildGraph returns a map of serviceName → list of service names it depends on,
-// for all BootDependency objects in the same namespace. The incoming bd takes
-// precedence over any existing object with the same name (handles updates).
+// validateDependencies checks that exactly one of service, host, workloadRef, jobRef or
+// resourceRef is set for each dependency, that its httpExpression and resourceRef
+// expression compile, and that no resourceRef refers to a Secret. It is shared by BootDependency and ClusterBootDependency.
+func validateDependencies(deps []corev1alpha1.ServiceDependency) error {
+	// Validate that exactly one of service, host, workloadRef, jobRef or resourceRef is set
+	// for each dependency.
+	for i, dep := range deps {
+		set := 0
+		for _, ok := range []bool{dep.Service != "", dep.Host != "", dep.WorkloadRef != nil, dep.JobRef != nil, dep.ResourceRef != nil} {
+			if ok {
+				set++
+			}
+		}
+		if set != 1 {
+			return field.Invalid(
+				field.NewPath("spec", "dependsOn").Index(i),
+				dep,
+				"exactly one of service, host, workloadRef, jobRef or resourceRef must be specified",
+			)
+		}
+		// CEL cannot be type-checked by the CRD schema, so compile it here rather
+		// than letting every probe fail at runtime.
+		if dep.HTTPExpression != "" {
+			if _, err := probe.CompileHTTPExpression(dep.HTTPExpression); err != nil {
+				return field.Invalid(
+					field.NewPath("spec", "dependsOn").Index(i).Child("httpExpression"),
+					dep.HTTPExpression,
+					err.Error(),
+				)
+			}
+		}
+		if dep.ResourceRef != nil {
+			if probe.ForbiddenResource(*dep.ResourceRef) {
+				return field.Forbidden(
+					field.NewPath("spec", "dependsOn").Index(i).Child("resourceRef", "kind"),
+					"resourceRef may not refer to a Secret",
+				)
+			}
+			if _, err := probe.CompileResourceExpression(dep.ResourceRef.Expression); err != nil {
+				return field.Invalid(
+					field.NewPath("spec", "dependsOn").Index(i).Child("resourceRef", "expression"),
+					dep.ResourceRef.Expression,
+					err.Error(),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// serviceWarning returns a warning when a service dependency refers to a Service, or a
+// port of it, that does not exist. The Service is looked up in the dependency's
+// namespace, or in namespace when it has none. BootDependencies are often applied
+// before the Services they wait for, so this never rejects the object.
+func (v *BootDependencyCustomValidator) serviceWarning(ctx context.Context, namespace string, i int, dep corev1alpha1.ServiceDependency) string {
+	if dep.Service == "" {
+		return ""
+	}
+	if dep.Namespace != "" {
+		namespace = dep.Namespace
+	}
+	path := field.NewPath("spec", "dependsOn").Index(i)
+
+	var svc corev1.Service
+	if err := v.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: dep.Service}, &svc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("%s: Service %q not found in namespace %q", path, dep.Service, namespace)
+		}
+		bootdependencylog.Error(err, "Failed to get Service", "service", dep.Service, "namespace", namespace)
+		return ""
+	}
+	// ExternalName Services have no ports of their own.
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return ""
+	}
+
+	switch {
+	case dep.PortName != "":
+		if _, ok := probe.NamedPort(&svc, dep.PortName); !ok {
+			return fmt.Sprintf("%s: Service %q has no port named %q", path, dep.Service, dep.PortName)
+		}
+	case dep.Port != 0:
+		if !slices.ContainsFunc(svc.Spec.Ports, func(p corev1.ServicePort) bool { return p.Port == dep.Port }) {
+			return fmt.Sprintf("%s: Service %q has no port %d", path, dep.Service, dep.Port)
+		}
+	}
+	return ""
+}
+
+// buildGraph returns a map of namespace/name → list of namespace/names it depends on,
+// for all BootDependency objects in the cluster, so that cycles through services in
+// other namespaces are found. The incoming bd takes precedence over any existing
+// object with the same name (handles updates).
 func (v *BootDependencyCustomValidator) buildGraph(ctx context.Context, bd *corev1alpha1.BootDependency) (map[string][]string, error) {
 	var list corev1alpha1.BootDependencyList
-	if err := v.Client.List(ctx, &list, client.InNamespace(bd.Namespace)); err != nil {
+	if err := v.Client.List(ctx, &list); err != nil {
 		return nil, fmt.Errorf("failed to list BootDependencies: %w", err)
 	}
 
-	graph := make(map[string][]string)
-
+	bds := make([]*corev1alpha1.BootDependency, 0, len(list.Items)+1)
 	for i := range list.Items {
 		existing := &list.Items[i]
-		if existing.Name == bd.Name {
-			// Skip — the incoming bd will overwrite this entry below.
+		if existing.Namespace == bd.Namespace && existing.Name == bd.Name {
+			// Skip — the incoming bd replaces this entry below.
 			continue
 		}
-		// Only in-cluster service deps participate in the cycle graph.
-		// External host deps are leaf nodes and can never form a BootDependency cycle.
-		deps := make([]string, 0, len(existing.Spec.DependsOn))
-		for _, dep := range existing.Spec.DependsOn {
-			if dep.Service != "" {
-				deps = append(deps, dep.Service)
-			}
+		bds = append(bds, existing)
+	}
+	// Add the incoming object (create or update).
+	bds = append(bds, bd)
+
+	// gatedBy maps the workloads named after their Service to the BootDependencies that
+	// gate them: by targetRef, or by their own name. Workloads matched by a selector are
+	// only known once they exist and are left out.
+	gatedBy := make(map[string][]string)
+	for _, b := range bds {
+		target := b.Name
+		switch {
+		case b.Spec.TargetRef != nil:
+			target = b.Spec.TargetRef.Name
+		case b.Spec.Selector != nil:
+			continue
 		}
-		graph[existing.Name] = deps
+		key := nodeKey(b.Namespace, target)
+		gatedBy[key] = append(gatedBy[key], nodeKey(b.Namespace, b.Name))
 	}
 
-	// Add the incoming object (create or update).
+	graph := make(map[string][]string, len(bds))
+	for _, b := range bds {
+		var edges []string
+		for _, workload := range graphEdges(b) {
+			edges = append(edges, gatedBy[workload]...)
+		}
+		graph[nodeKey(b.Namespace, b.Name)] = edges
+	}
+
+	return graph, nil
+}
+
+// graphEdges returns the workloads bd depends on. Only in-cluster service and
+// workload deps participate in the cycle graph. External host deps are leaf nodes
+// and can never form a BootDependency cycle.
+func graphEdges(bd *corev1alpha1.BootDependency) []string {
 	deps := make([]string, 0, len(bd.Spec.DependsOn))
 	for _, dep := range bd.Spec.DependsOn {
-		if dep.Service != "" {
-			deps = append(deps, dep.Service)
+		if node := graphNode(dep, bd.Namespace); node != "" {
+			deps = append(deps, node)
 		}
 	}
-	graph[bd.Name] = deps
+	return deps
+}
 
-	return graph, nil
+// graphNode returns the namespace/name of the workload a dependency points at, or ""
+// when it cannot be part of a cycle. Workloads are assumed to be named after their
+// Service, so a workloadRef is an edge just like a service. namespace is the namespace
+// of the BootDependency that declares dep.
+func graphNode(dep corev1alpha1.ServiceDependency, namespace string) string {
+	if dep.WorkloadRef != nil {
+		return nodeKey(namespace, dep.WorkloadRef.Name)
+	}
+	if dep.Service == "" {
+		return ""
+	}
+	if dep.Namespace != "" {
+		namespace = dep.Namespace
+	}
+	return nodeKey(namespace, dep.Service)
+}
+
+// nodeKey identifies a BootDependency or a workload in the cycle graph.
+func nodeKey(namespace, name string) string {
+	return namespace + "/" + name
 }
 
 // detectCycle runs a DFS from `start` and returns the cycle path if one is found,
-// or nil if the graph is acyclic from that node.
+// or nil if the graph is acyclic from that node. Nodes whose descendants have all been
+// explored are never visited again, so the search is linear in the size of the graph.
 func detectCycle(start string, graph map[string][]string) []string {
-	visited := make(map[string]bool)
+	onPath := make(map[string]bool)
+	done := make(map[string]bool)
 	path := make([]string, 0)
 
 	var dfs func(node string) []string
 	dfs = func(node string) []string {
-		if visited[node] {
+		if onPath[node] {
 			// Found the cycle — return the path from where we first saw this node.
-			for i, n := range path {
-				if n == node {
-					cycle := make([]string, len(path)-i+1)
-					copy(cycle, path[i:])
-					cycle[len(cycle)-1] = node
-					return cycle
-				}
-			}
-			return []string{node}
+			i := slices.Index(path, node)
+			return append(slices.Clone(path[i:]), node)
+		}
+		if done[node] {
+			return nil
 		}
 
-		visited[node] = true
+		onPath[node] = true
 		path = append(path, node)
 
 		for _, neighbour := range graph[node] {
@@ -166,7 +310,8 @@ func detectCycle(start string, graph map[string][]string) []string {
 		}
 
 		path = path[:len(path)-1]
-		visited[node] = false
+		onPath[node] = false
+		done[node] = true
 		return nil
 	}
 
@@ -18,21 +18,49 @@ package v1alpha1
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
 )
 
+// requestContext returns a context carrying an admission request sent by username in
+// groups, as the webhook server passes to the validators.
+func requestContext(username string, groups ...string) context.Context {
+	return admission.NewContextWithRequest(context.Background(), admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+		},
+	})
+}
+
 var _ = Describe("BootDependency Webhook", func() {
-	ctx := context.Background()
+	ctx := requestContext("admin", "system:masters")
+
+	// createService creates a Service in the default namespace for the current spec.
+	createService := func(name string, ports ...corev1.ServicePort) {
+		svc := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: ports},
+		}
+		Expect(k8sClient.Create(ctx, svc)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, svc)
+	}
 
 	Context("When creating a BootDependency with no circular dependencies", func() {
 		It("should allow creation", func() {
+			createService("postgres", corev1.ServicePort{Name: "postgres", Port: 5432})
 			bd := &corev1alpha1.BootDependency{
 				ObjectMeta: metav1.ObjectMeta{Name: "svc-a", Namespace: "default"},
 				Spec: corev1alpha1.BootDependencySpec{
@@ -61,7 +89,7 @@ var _ = Describe("BootDependency Webhook", func() {
 			validator := &BootDependencyCustomValidator{Client: k8sClient}
 			_, err := validator.ValidateCreate(ctx, bd)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("exactly one of service or host must be specified"))
+			Expect(err.Error()).To(ContainSubstring("exactly one of service, host, workloadRef, jobRef or resourceRef must be specified"))
 		})
 	})
 
@@ -78,7 +106,7 @@ var _ = Describe("BootDependency Webhook", func() {
 			validator := &BootDependencyCustomValidator{Client: k8sClient}
 			_, err := validator.ValidateCreate(ctx, bd)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("exactly one of service or host must be specified"))
+			Expect(err.Error()).To(ContainSubstring("exactly one of service, host, workloadRef, jobRef or resourceRef must be specified"))
 		})
 	})
 
@@ -131,6 +159,88 @@ var _ = Describe("BootDependency Webhook", func() {
 		})
 	})
 
+	Context("When a service dependency refers to a Service or port that does not exist", func() {
+		validate := func(dep corev1alpha1.ServiceDependency) admission.Warnings {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-warn", Namespace: "default"},
+				Spec:       corev1alpha1.BootDependencySpec{DependsOn: []corev1alpha1.ServiceDependency{dep}},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			return warnings
+		}
+
+		It("should warn about a missing Service", func() {
+			warnings := validate(corev1alpha1.ServiceDependency{Service: "missing", Port: 8080})
+			Expect(warnings).To(ConsistOf(`spec.dependsOn[0]: Service "missing" not found in namespace "default"`))
+		})
+
+		It("should warn about a port the Service does not expose", func() {
+			createService("api", corev1.ServicePort{Name: "http", Port: 8080})
+			Expect(validate(corev1alpha1.ServiceDependency{Service: "api", Port: 9090})).
+				To(ConsistOf(`spec.dependsOn[0]: Service "api" has no port 9090`))
+			Expect(validate(corev1alpha1.ServiceDependency{Service: "api", PortName: "grpc"})).
+				To(ConsistOf(`spec.dependsOn[0]: Service "api" has no port named "grpc"`))
+			Expect(validate(corev1alpha1.ServiceDependency{Service: "api", PortName: "http"})).To(BeEmpty())
+		})
+
+		It("should look up a Service in the dependency's namespace", func() {
+			createService("api", corev1.ServicePort{Name: "http", Port: 8080})
+			Expect(validate(corev1alpha1.ServiceDependency{Service: "api", Namespace: "platform", Port: 8080})).
+				To(ConsistOf(`spec.dependsOn[0]: Service "api" not found in namespace "platform"`))
+		})
+
+		It("should deny a Service in another namespace the requesting user may not get", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-cross-namespace", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{{Service: "etcd", Namespace: "kube-system", Port: 2379}},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(requestContext("tenant"), bd)
+			Expect(err).To(MatchError(ContainSubstring(
+				`spec.dependsOn[0].namespace: Forbidden: user "tenant" may not get services "etcd" in namespace "kube-system"`)))
+
+			_, err = validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Naming the BootDependency's own namespace does not reach across namespaces.
+			bd.Spec.DependsOn[0].Namespace = "default"
+			_, err = validator.ValidateCreate(requestContext("tenant"), bd)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject namespace on a host dependency (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-namespace-host", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "api.example.com", Namespace: "platform", Port: 8080},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("namespace requires service to be set"))
+		})
+
+		It("should reject portName on a host dependency (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-portname-host", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "api.example.com", PortName: "http"},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("portName requires service to be set"))
+		})
+	})
+
 	Context("CEL validation: httpScheme and insecure require httpPath", func() {
 		It("should reject httpScheme set without httpPath (via API server)", func() {
 			bd := &corev1alpha1.BootDependency{
@@ -233,42 +343,796 @@ var _ = Describe("BootDependency Webhook", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("httpExpectedStatuses requires httpPath to be set"))
 		})
-	})
 
-	Context("When creating a BootDependency that introduces a circular dependency", func() {
-		BeforeEach(func() {
-			bdB := &corev1alpha1.BootDependency{
-				ObjectMeta: metav1.ObjectMeta{Name: "svc-b", Namespace: "default"},
+		It("should reject httpExpression set without httpPath (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-expression-no-path", Namespace: "default"},
 				Spec: corev1alpha1.BootDependencySpec{
 					DependsOn: []corev1alpha1.ServiceDependency{
-						{Service: "svc-c", Port: 8080},
+						{Service: "api", Port: 8080, HTTPExpression: "status == 200"},
 					},
 				},
 			}
-			Expect(k8sClient.Create(ctx, bdB)).To(Succeed())
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("httpExpression requires httpPath to be set"))
 		})
+	})
 
-		AfterEach(func() {
-			bd := &corev1alpha1.BootDependency{}
-			err := k8sClient.Get(ctx, types.NamespacedName{Name: "svc-b", Namespace: "default"}, bd)
-			if err == nil {
-				_ = k8sClient.Delete(ctx, bd)
+	Context("When creating a BootDependency with an httpExpression", func() {
+		It("should reject an expression that does not compile", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-bad-expression", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "api", Port: 8080, HTTPPath: "/health", HTTPExpression: `body.status ==`},
+					},
+				},
 			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dependsOn[0].httpExpression"))
 		})
 
-		It("should deny creation when svc-c depends on svc-b (cycle: svc-c → svc-b → svc-c)", func() {
-			bdC := &corev1alpha1.BootDependency{
-				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+		It("should allow a boolean expression over the response", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-good-expression", Namespace: "default"},
 				Spec: corev1alpha1.BootDependencySpec{
 					DependsOn: []corev1alpha1.ServiceDependency{
-						{Service: "svc-b", Port: 8080},
+						{Service: "api", Port: 8080, HTTPPath: "/health", HTTPExpression: `body.status == "UP" && body.db.ready`},
 					},
 				},
 			}
 			validator := &BootDependencyCustomValidator{Client: k8sClient}
-			_, err := validator.ValidateCreate(ctx, bdC)
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("CEL validation: grpc probe", func() {
+		It("should reject grpc combined with httpPath (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-grpc-and-http", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "api", Port: 9090, HTTPPath: "/healthz", GRPC: &corev1alpha1.GRPCProbe{}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
 			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("circular dependency"))
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+
+		It("should reject grpc.insecure without grpc.tls (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-grpc-insecure-no-tls", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "api", Port: 9090, GRPC: &corev1alpha1.GRPCProbe{Insecure: true}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("insecure requires tls to be enabled"))
+		})
+	})
+
+	Context("CEL validation: postgres probe", func() {
+		It("should reject postgres combined with grpc (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-postgres-and-grpc", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Service:  "db",
+							Port:     5432,
+							GRPC:     &corev1alpha1.GRPCProbe{},
+							Postgres: &corev1alpha1.PostgresProbe{},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+
+		It("should reject postgres.query without credentialsSecretRef (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-postgres-query-no-creds", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "db", Port: 5432, Postgres: &corev1alpha1.PostgresProbe{Query: "SELECT 1"}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("query requires credentialsSecretRef to be set"))
+		})
+	})
+
+	Context("CEL validation: mysql probe", func() {
+		It("should reject mysql combined with postgres (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-mysql-and-postgres", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Service:  "db",
+							Port:     3306,
+							Postgres: &corev1alpha1.PostgresProbe{},
+							MySQL:    &corev1alpha1.MySQLProbe{},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+	})
+
+	Context("CEL validation: redis probe", func() {
+		It("should reject redis combined with httpPath (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-redis-and-http", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "cache", Port: 6379, HTTPPath: "/healthz", Redis: &corev1alpha1.RedisProbe{}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+	})
+
+	Context("CEL validation: kafka probe", func() {
+		It("should reject kafka combined with redis (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-kafka-and-redis", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Service: "kafka",
+							Port:    9092,
+							Redis:   &corev1alpha1.RedisProbe{},
+							Kafka:   &corev1alpha1.KafkaProbe{Topics: []string{"orders"}},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+	})
+
+	Context("CEL validation: amqp probe", func() {
+		It("should reject amqp combined with kafka (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-amqp-and-kafka", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Service: "broker",
+							Port:    5672,
+							Kafka:   &corev1alpha1.KafkaProbe{},
+							AMQP:    &corev1alpha1.AMQPProbe{},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+	})
+
+	Context("CEL validation: mongodb probe", func() {
+		It("should reject mongodb combined with grpc (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-mongodb-and-grpc", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Service: "mongo",
+							Port:    27017,
+							GRPC:    &corev1alpha1.GRPCProbe{},
+							MongoDB: &corev1alpha1.MongoDBProbe{RequireWritablePrimary: true},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+	})
+
+	Context("CEL validation: dns probe", func() {
+		It("should reject a dependency without port unless dns or workloadRef is set (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-no-port", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "db.example.com"},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("port or portName is required unless dns, endpoints, workloadRef, jobRef or resourceRef is set"))
+		})
+
+		It("should reject dns combined with httpPath (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-dns-and-http", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "db.example.com", Port: 443, HTTPPath: "/healthz", DNS: &corev1alpha1.DNSProbe{}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+
+		It("should reject an unsupported record type (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-dns-mx", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "example.com", DNS: &corev1alpha1.DNSProbe{Type: "MX"}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dependsOn[0].dns.type"))
+		})
+	})
+
+	Context("CEL validation: tls probe", func() {
+		It("should reject tls combined with grpc (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-tls-and-grpc", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "api", Port: 443, GRPC: &corev1alpha1.GRPCProbe{TLS: true}, TLS: &corev1alpha1.TLSProbe{}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of httpPath, grpc, postgres, mysql, redis, kafka, amqp, mongodb, dns, tls, workloadRef, jobRef, resourceRef or endpoints may be set"))
+		})
+
+		It("should reject a minRemainingValidity that is not a duration (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-tls-bad-validity", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "api.example.com", Port: 443, TLS: &corev1alpha1.TLSProbe{MinRemainingValidity: "30d"}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dependsOn[0].tls.minRemainingValidity"))
+		})
+	})
+
+	Context("CEL validation: endpoints probe", func() {
+		It("should reject endpoints on a host dependency (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-endpoints-host", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Host: "db.example.com", Endpoints: &corev1alpha1.EndpointsProbe{}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("endpoints requires service to be set"))
+		})
+
+		It("should allow endpoints on a service dependency without a port", func() {
+			createService("postgres", corev1.ServicePort{Name: "postgres", Port: 5432})
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-endpoints", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "postgres", Endpoints: &corev1alpha1.EndpointsProbe{MinReady: 2}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+	})
+
+	Context("CEL validation: caBundleRef and clientCertSecretRef", func() {
+		It("should reject a client certificate on a plain HTTP probe (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-mtls-http", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:                "api.example.com",
+							Port:                8443,
+							HTTPPath:            "/healthz",
+							ClientCertSecretRef: &corev1alpha1.ClientCertSecretRef{Name: "api-client"},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("clientCertSecretRef requires httpScheme to be https"))
+		})
+
+		It("should reject a caBundleRef that sets both a Secret and a ConfigMap (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-ca-both", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:       "api.example.com",
+							Port:       8443,
+							HTTPPath:   "/healthz",
+							HTTPScheme: "https",
+							CABundleRef: &corev1alpha1.CABundleRef{
+								SecretKeyRef:    &corev1alpha1.SecretKeySelector{Name: "ca", Key: "ca.crt"},
+								ConfigMapKeyRef: &corev1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+							},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of secretKeyRef or configMapKeyRef must be set"))
+		})
+
+		It("should accept an HTTPS probe with a CA bundle and client certificate (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-mtls", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:       "api.example.com",
+							Port:       8443,
+							HTTPPath:   "/healthz",
+							HTTPScheme: "https",
+							CABundleRef: &corev1alpha1.CABundleRef{
+								ConfigMapKeyRef: &corev1alpha1.ConfigMapKeySelector{Name: "internal-ca", Key: "ca.crt"},
+							},
+							ClientCertSecretRef: &corev1alpha1.ClientCertSecretRef{Name: "api-client"},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, bd)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, bd)
+		})
+	})
+
+	Context("CEL validation: proxy", func() {
+		It("should reject a proxy on a protocol probe (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-proxy-redis", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:  "cache.example.com",
+							Port:  6379,
+							Redis: &corev1alpha1.RedisProbe{},
+							Proxy: &corev1alpha1.ProxySpec{URL: "http://proxy:3128"},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("proxy and dualStack are only supported for TCP and HTTP probes"))
+		})
+
+		It("should reject a proxy that sets both url and disabled (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-proxy-both", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:  "db.example.com",
+							Port:  5432,
+							Proxy: &corev1alpha1.ProxySpec{URL: "http://proxy:3128", Disabled: true},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of url or disabled must be set"))
+		})
+	})
+
+	Context("CEL validation: IPv6 and dual-stack", func() {
+		It("should reject an IPv6 host in brackets (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-ipv6-brackets", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{{Host: "[2001:db8::10]", Port: 5432}},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("IPv6 addresses must not be enclosed in brackets"))
+		})
+
+		It("should reject dualStack together with a proxy URL (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-dualstack-proxy", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{
+							Host:      "db.example.com",
+							Port:      5432,
+							DualStack: true,
+							Proxy:     &corev1alpha1.ProxySpec{URL: "http://proxy:3128"},
+						},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("dualStack cannot be combined with proxy.url"))
+		})
+	})
+
+	Context("When creating a BootDependency with a workloadRef", func() {
+		It("should allow a workloadRef without a port", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-workload", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{WorkloadRef: &corev1alpha1.WorkloadRef{Kind: "StatefulSet", Name: "postgres"}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should deny a workloadRef combined with service", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-workload-and-service", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "postgres", Port: 5432, WorkloadRef: &corev1alpha1.WorkloadRef{Kind: "Deployment", Name: "postgres"}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of service, host, workloadRef, jobRef or resourceRef must be specified"))
+		})
+
+		It("should reject an unsupported workload kind (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-workload-job", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{WorkloadRef: &corev1alpha1.WorkloadRef{Kind: "Job", Name: "migrate"}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dependsOn[0].workloadRef.kind"))
+		})
+	})
+
+	Context("When creating a BootDependency with a jobRef", func() {
+		It("should allow a jobRef with a selector and no port", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-job", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{JobRef: &corev1alpha1.JobRef{Selector: map[string]string{"app": "migrate"}}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should reject a jobRef with both name and selector (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-job-both", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{JobRef: &corev1alpha1.JobRef{Name: "migrate", Selector: map[string]string{"app": "migrate"}}},
+					},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exactly one of name or selector must be set"))
+		})
+	})
+
+	Context("When creating a BootDependency with a resourceRef", func() {
+		resourceRef := func(expr string) *corev1alpha1.ResourceRef {
+			return &corev1alpha1.ResourceRef{APIVersion: "v1", Kind: "PersistentVolumeClaim", Name: "data", Expression: expr}
+		}
+
+		It("should allow a resourceRef with a valid expression and no port", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-resource", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{ResourceRef: resourceRef(`object.status.phase == "Bound"`)},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			warnings, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("should deny a resourceRef whose expression does not compile", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-resource-invalid", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{ResourceRef: resourceRef(`object.status.phase ==`)},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.dependsOn[0].resourceRef.expression"))
+		})
+
+		It("should deny a resourceRef to a Secret", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-resource-secret", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{{ResourceRef: &corev1alpha1.ResourceRef{
+						APIVersion: "v1", Kind: "Secret", Namespace: "kube-system", Name: "bootstrap-token", Expression: "has(object.data)",
+					}}},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bd)
+			Expect(err).To(MatchError(ContainSubstring("spec.dependsOn[0].resourceRef.kind: Forbidden: resourceRef may not refer to a Secret")))
+		})
+
+		It("should deny a resourceRef to an object the requesting user may not get", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-resource-forbidden", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{{ResourceRef: &corev1alpha1.ResourceRef{
+						APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "kubeadm-config", Expression: "has(object.data)",
+					}}},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(requestContext("tenant"), bd)
+			Expect(err).To(MatchError(ContainSubstring(
+				`spec.dependsOn[0].resourceRef: Forbidden: user "tenant" may not get configmaps "kubeadm-config" in namespace "kube-system"`)))
+
+			_, err = validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should deny a header from a Secret the requesting user may not get", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-header-forbidden", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{{
+						Host:     "collector.example.com",
+						Port:     443,
+						HTTPPath: "/",
+						HTTPHeaders: []corev1alpha1.HTTPHeader{{Name: "Authorization", ValueFrom: &corev1alpha1.HTTPHeaderSource{
+							SecretKeyRef: &corev1alpha1.SecretKeySelector{Name: "payments-db", Key: "password"},
+						}}},
+					}},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(requestContext("tenant"), bd)
+			Expect(err).To(MatchError(ContainSubstring(
+				`spec.dependsOn[0]: Forbidden: user "tenant" may not get secrets "payments-db" in namespace "default"`)))
+
+			_, err = validator.ValidateCreate(ctx, bd)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When creating a BootDependency that introduces a circular dependency", func() {
+		BeforeEach(func() {
+			bdB := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-b", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "svc-c", Port: 8080},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, bdB)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			bd := &corev1alpha1.BootDependency{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Name: "svc-b", Namespace: "default"}, bd)
+			if err == nil {
+				_ = k8sClient.Delete(ctx, bd)
+			}
+		})
+
+		It("should deny creation when svc-c depends on svc-b (cycle: svc-c → svc-b → svc-c)", func() {
+			bdC := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "svc-b", Port: 8080},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bdC)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("circular dependency"))
+		})
+
+		It("should deny creation when the cycle goes through a Deployment workloadRef", func() {
+			bdC := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{WorkloadRef: &corev1alpha1.WorkloadRef{Kind: "Deployment", Name: "svc-b"}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bdC)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("circular dependency"))
+		})
+
+		It("should deny creation when the cycle goes through a StatefulSet workloadRef", func() {
+			bdC := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					TargetKind: "StatefulSet",
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{WorkloadRef: &corev1alpha1.WorkloadRef{Kind: "StatefulSet", Name: "svc-b"}},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bdC)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("circular dependency detected: svc-c → svc-b → svc-c"))
+		})
+
+		It("should deny a cycle through a Service in another namespace", func() {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "platform"}}
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).To(Succeed())
+			bdKafka := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "kafka", Namespace: "platform"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "svc-c", Namespace: "default", Port: 8080},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, bdKafka)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, bdKafka)
+
+			bdC := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "kafka", Namespace: "platform", Port: 9092},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bdC)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("circular dependency detected: svc-c → platform/kafka → svc-c"))
+
+			// A Service with the same name in the BootDependency's own namespace is a different node.
+			bdC.Spec.DependsOn[0].Namespace = ""
+			_, err = validator.ValidateCreate(ctx, bdC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should follow targetRef to the BootDependency that gates a Service's workload", func() {
+			bdPayments := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "payments", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					TargetRef: &corev1alpha1.TargetRef{Kind: "Deployment", Name: "prod-payments"},
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "svc-b", Port: 8080},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, bdPayments)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, bdPayments)
+
+			bdC := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc-c", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					DependsOn: []corev1alpha1.ServiceDependency{
+						{Service: "prod-payments", Port: 8080},
+					},
+				},
+			}
+			validator := &BootDependencyCustomValidator{Client: k8sClient}
+			_, err := validator.ValidateCreate(ctx, bdC)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("circular dependency detected: svc-c → payments → svc-b → svc-c"))
+
+			// payments no longer gates a workload named after its own name.
+			bdC.Spec.DependsOn[0].Service = "payments"
+			_, err = validator.ValidateCreate(ctx, bdC)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When the dependency graph has many paths between two BootDependencies", func() {
+		It("should explore every BootDependency only once", func() {
+			// 40 layers of two BootDependencies that each depend on both of the next
+			// layer give 2^40 paths from the first layer to the last.
+			graph := make(map[string][]string)
+			for layer := range 40 {
+				next := []string{fmt.Sprintf("default/a%d", layer+1), fmt.Sprintf("default/b%d", layer+1)}
+				graph[fmt.Sprintf("default/a%d", layer)] = next
+				graph[fmt.Sprintf("default/b%d", layer)] = next
+			}
+			done := make(chan []string)
+			go func() { done <- detectCycle("default/a0", graph) }()
+			Eventually(done).WithTimeout(time.Second).Should(Receive(BeNil()))
+
+			graph["default/b40"] = []string{"default/a0"}
+			Expect(detectCycle("default/a0", graph)).To(HaveLen(42))
+		})
+	})
+
+	Context("When a BootDependency sets targetRef and selector", func() {
+		It("should reject the combination (via API server)", func() {
+			bd := &corev1alpha1.BootDependency{
+				ObjectMeta: metav1.ObjectMeta{Name: "target-and-selector", Namespace: "default"},
+				Spec: corev1alpha1.BootDependencySpec{
+					TargetRef: &corev1alpha1.TargetRef{Kind: "Deployment", Name: "api"},
+					Selector:  &metav1.LabelSelector{MatchLabels: map[string]string{"app": "api"}},
+					DependsOn: []corev1alpha1.ServiceDependency{{Service: "postgres", Port: 5432}},
+				},
+			}
+			err := k8sClient.Create(ctx, bd)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("only one of targetRef or selector may be set"))
 		})
 	})
 })
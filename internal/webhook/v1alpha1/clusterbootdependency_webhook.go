@@ -0,0 +1,71 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var clusterbootdependencylog = logf.Log.WithName("clusterbootdependency-webhook")
+
+// +kubebuilder:webhook:path=/validate-core-bootchain-operator-ruicoelho-dev-v1alpha1-clusterbootdependency,mutating=false,failurePolicy=fail,sideEffects=None,groups=core.bootchain-operator.ruicoelho.dev,resources=clusterbootdependencies,verbs=create;update,versions=v1alpha1,name=vclusterbootdependency-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// ClusterBootDependencyCustomValidator validates ClusterBootDependency resources on
+// create and update. Its dependencies are validated like those of a BootDependency;
+// they are not part of the cycle check because they apply to many namespaces.
+type ClusterBootDependencyCustomValidator struct {
+	Client client.Client
+}
+
+// SetupClusterBootDependencyWebhookWithManager registers the webhook for
+// ClusterBootDependency in the manager.
+func SetupClusterBootDependencyWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &corev1alpha1.ClusterBootDependency{}).
+		WithValidator(&ClusterBootDependencyCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+func (v *ClusterBootDependencyCustomValidator) ValidateCreate(ctx context.Context, cbd *corev1alpha1.ClusterBootDependency) (admission.Warnings, error) {
+	clusterbootdependencylog.Info("Validating ClusterBootDependency create", "name", cbd.Name)
+	return nil, v.validate(ctx, cbd)
+}
+
+func (v *ClusterBootDependencyCustomValidator) ValidateUpdate(ctx context.Context, _ *corev1alpha1.ClusterBootDependency, newCBD *corev1alpha1.ClusterBootDependency) (admission.Warnings, error) {
+	clusterbootdependencylog.Info("Validating ClusterBootDependency update", "name", newCBD.Name)
+	return nil, v.validate(ctx, newCBD)
+}
+
+func (v *ClusterBootDependencyCustomValidator) ValidateDelete(_ context.Context, _ *corev1alpha1.ClusterBootDependency) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validate checks the dependencies like those of a BootDependency. A resourceRef
+// without a namespace is read from every selected namespace, so its author must be
+// allowed to read it in all of them.
+func (v *ClusterBootDependencyCustomValidator) validate(ctx context.Context, cbd *corev1alpha1.ClusterBootDependency) error {
+	if err := validateDependencies(cbd.Spec.DependsOn); err != nil {
+		return err
+	}
+	return authorizeDependencies(ctx, v.Client, "", cbd.Spec.DependsOn)
+}
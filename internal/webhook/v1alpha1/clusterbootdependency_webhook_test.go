@@ -0,0 +1,77 @@
+/*
+Copyright 2026.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/user-cube/bootchain-operator/api/v1alpha1"
+)
+
+var _ = Describe("ClusterBootDependency Webhook", func() {
+	ctx := requestContext("admin", "system:masters")
+	validator := &ClusterBootDependencyCustomValidator{Client: k8sClient}
+
+	clusterBootDependency := func(deps ...corev1alpha1.ServiceDependency) *corev1alpha1.ClusterBootDependency {
+		return &corev1alpha1.ClusterBootDependency{
+			ObjectMeta: metav1.ObjectMeta{Name: "platform-baseline"},
+			Spec: corev1alpha1.ClusterBootDependencySpec{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"mesh": "enabled"}},
+				DependsOn:         deps,
+			},
+		}
+	}
+
+	It("should allow valid dependencies", func() {
+		cbd := clusterBootDependency(
+			corev1alpha1.ServiceDependency{Service: "istiod", Namespace: "istio-system", Port: 15012},
+			corev1alpha1.ServiceDependency{Host: "vault.example.com", Port: 8200},
+		)
+		warnings, err := validator.ValidateCreate(ctx, cbd)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("should deny a dependency with both service and host set", func() {
+		cbd := clusterBootDependency(corev1alpha1.ServiceDependency{Service: "istiod", Host: "vault.example.com", Port: 8200})
+		_, err := validator.ValidateCreate(ctx, cbd)
+		Expect(err).To(MatchError(ContainSubstring("exactly one of service, host, workloadRef, jobRef or resourceRef must be specified")))
+	})
+
+	It("should deny an update with an invalid httpExpression", func() {
+		old := clusterBootDependency(corev1alpha1.ServiceDependency{Host: "vault.example.com", Port: 8200})
+		cbd := old.DeepCopy()
+		cbd.Spec.DependsOn[0].HTTPPath = "/v1/sys/health"
+		cbd.Spec.DependsOn[0].HTTPExpression = `body.status ==`
+		_, err := validator.ValidateUpdate(ctx, old, cbd)
+		Expect(err).To(MatchError(ContainSubstring("spec.dependsOn[0].httpExpression")))
+	})
+
+	It("should require access to a resourceRef in every namespace when it has no namespace", func() {
+		cbd := clusterBootDependency(corev1alpha1.ServiceDependency{ResourceRef: &corev1alpha1.ResourceRef{
+			APIVersion: "v1", Kind: "ConfigMap", Name: "mesh-config", Expression: "has(object.data)",
+		}})
+		_, err := validator.ValidateCreate(requestContext("tenant"), cbd)
+		Expect(err).To(MatchError(ContainSubstring(`user "tenant" may not get configmaps "mesh-config" cluster-wide`)))
+
+		_, err = validator.ValidateCreate(ctx, cbd)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})